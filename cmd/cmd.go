@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"github.com/VanMoof/gopenapi/interpret"
 	"github.com/spf13/cobra"
 	"os"
 )
@@ -17,20 +18,45 @@ func Execute() error {
 
 	var format string
 	var output string
+	var typed bool
+	var buildTags []string
+	var schemaNaming string
+	var typeMappings string
+	var nameTag string
+	options := interpret.Options{}
 	var generateSpecCmd = &cobra.Command{
 		Use:   "spec [optional path]",
 		Short: "The spec generator utility",
 		Long:  "The spec generator utility can GenerateSpec specifications from source code",
 
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := GenerateSpec(format, output, args); err != nil {
-				println(err)
+			var err error
+			options.SchemaNaming, err = interpret.ParseSchemaNaming(schemaNaming)
+			if err == nil {
+				options.NameTag, err = interpret.ParseNameTag(nameTag)
+			}
+			if err == nil {
+				options.TypeMappings, err = ResolveTypeMappings(typeMappings)
+			}
+			if err == nil {
+				interpreter := ResolveInterpreter(typed, buildTags, options)
+				err = GenerateSpec(format, output, interpreter, args)
+			}
+			if err != nil {
+				println(err.Error())
 				os.Exit(1)
 			}
 		},
 	}
 	generateSpecCmd.Flags().StringVarP(&format, "format", "f", "json", "The format of the output. May be json or yaml")
 	generateSpecCmd.Flags().StringVarP(&output, "output", "o", "-", "Where the output should be directed. May be '-' (stdout) or a path to a file")
+	generateSpecCmd.Flags().BoolVar(&options.EmbeddedAsAllOf, "embedded-all-of", false, "Compose the schemas of structs with the schemas of their embedded structs using allOf, instead of flattening them")
+	generateSpecCmd.Flags().BoolVar(&options.IncludeReferencedTypes, "include-referenced", false, "Generate the schemas of the types that are referred to by other schemas, also when they are not annotated")
+	generateSpecCmd.Flags().StringVar(&schemaNaming, "schema-naming", string(interpret.LowerCamelSchemaNaming), "How schemas are named after their types. May be lowerCamel, asIs, packageQualified or packagePrefixed")
+	generateSpecCmd.Flags().StringVar(&nameTag, "name-tag", string(interpret.JSONNameTag), "The struct tags that name the properties of schemas. May be json or yaml")
+	generateSpecCmd.Flags().StringVar(&typeMappings, "type-mappings", "", "A YAML or JSON file that maps types, by their import path and name, to the schemas of their JSON representation")
+	generateSpecCmd.Flags().BoolVar(&typed, "typed", false, "Load whole packages and resolve types with the type checker")
+	generateSpecCmd.Flags().StringSliceVar(&buildTags, "tags", nil, "The build tags that are satisfied while matching the build constraints of files")
 
 	generateCmd.AddCommand(generateSpecCmd)
 	rootCmd.AddCommand(generateCmd)
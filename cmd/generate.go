@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"github.com/VanMoof/gopenapi/generate"
 	"github.com/VanMoof/gopenapi/interpret"
+	"github.com/VanMoof/gopenapi/models"
+	"gopkg.in/yaml.v3"
 	"io"
 	"os"
 	"path/filepath"
 )
 
-func GenerateSpec(format string, output string, args []string) error {
+func GenerateSpec(format string, output string, interpreter interpret.Interpreter, args []string) error {
 	givenPath := ""
 	if len(args) != 0 {
 		givenPath = args[0]
@@ -24,7 +26,33 @@ func GenerateSpec(format string, output string, args []string) error {
 		return err
 	}
 	s := ResolveOutputSink(format, out)
-	return generate.Generate(generate.GoFileVisitor{BasePath: normalizedPath}, &interpret.ASTInterpreter{}, s)
+	return generate.Generate(generate.GoFileVisitor{BasePath: normalizedPath}, interpreter, s)
+}
+
+// ResolveInterpreter returns the interpreter that resolves types with the type checker when typed is set, or the one
+// that resolves types by their names when it is not
+func ResolveInterpreter(typed bool, buildTags []string, options interpret.Options) interpret.Interpreter {
+	if typed {
+		return &interpret.PackagesInterpreter{Options: options, BuildTags: buildTags}
+	}
+	return &interpret.ASTInterpreter{Options: options, BuildTags: buildTags}
+}
+
+// ResolveTypeMappings reads the type mappings of the YAML or JSON file at the path. An empty path maps no types
+func ResolveTypeMappings(path string) (map[string]*models.Schema, error) {
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the type mappings: %w", err)
+	}
+	typeMappings := map[string]*models.Schema{}
+	err = yaml.Unmarshal(content, &typeMappings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the type mappings in %s: %w", path, err)
+	}
+	return typeMappings, nil
 }
 
 func ResolveOutputSink(format string, out io.WriteCloser) generate.Sink {
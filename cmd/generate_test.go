@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"github.com/VanMoof/gopenapi/cmd"
+	"github.com/VanMoof/gopenapi/interpret"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
 	"io"
@@ -18,7 +19,7 @@ func TestGenerateSpec_YAMLFile(t *testing.T) {
 
 	tempFile, tempFileError := ioutil.TempFile("", "*.yaml")
 	a.NoError(tempFileError)
-	a.NoError(cmd.GenerateSpec("yaml", tempFile.Name(), []string{"../interpret/_test_files"}))
+	a.NoError(cmd.GenerateSpec("yaml", tempFile.Name(), &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}, []string{"../interpret/_test_files"}))
 
 	decoded := map[string]interface{}{}
 	a.NoError(yaml.NewDecoder(tempFile).Decode(&decoded))
@@ -30,18 +31,32 @@ func TestGenerateSpec_JSONFile(t *testing.T) {
 
 	tempFile, tempFileError := ioutil.TempFile("", "*.yaml")
 	a.NoError(tempFileError)
-	a.NoError(cmd.GenerateSpec("json", tempFile.Name(), []string{"../interpret/_test_files"}))
+	a.NoError(cmd.GenerateSpec("json", tempFile.Name(), &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}, []string{"../interpret/_test_files"}))
 
 	decoded := map[string]interface{}{}
 	a.NoError(json.NewDecoder(tempFile).Decode(&decoded))
 	a.Equal("3.0.2", decoded["openapi"])
 }
 
+func TestGenerateSpec_Typed(t *testing.T) {
+	a := assert.New(t)
+
+	tempFile, tempFileError := ioutil.TempFile("", "*.json")
+	a.NoError(tempFileError)
+	interpreter := cmd.ResolveInterpreter(true, []string{"testResource"}, interpret.Options{})
+	a.NoError(cmd.GenerateSpec("json", tempFile.Name(), interpreter, []string{"../interpret/_test_files"}))
+
+	decoded := map[string]interface{}{}
+	a.NoError(json.NewDecoder(tempFile).Decode(&decoded))
+	a.Equal("3.0.2", decoded["openapi"])
+	a.Contains(decoded["components"].(map[string]interface{})["schemas"], "measurement")
+}
+
 func TestGenerateSpec_JSONStdout(t *testing.T) {
 	a := assert.New(t)
 
 	writeFunc := func() {
-		a.NoError(cmd.GenerateSpec("json", "-", []string{"../interpret/_test_files"}))
+		a.NoError(cmd.GenerateSpec("json", "-", &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}, []string{"../interpret/_test_files"}))
 	}
 	assertFunc := func(out string) {
 		decoded := map[string]interface{}{}
@@ -79,6 +94,30 @@ func TestResolveOutputWriter_File(t *testing.T) {
 	a.Equal("a", string(content))
 }
 
+func TestResolveTypeMappings(t *testing.T) {
+	a := assert.New(t)
+
+	tempFile, tempFileError := ioutil.TempFile("", "*.yaml")
+	a.NoError(tempFileError)
+	_, writeError := tempFile.WriteString("github.com/google/uuid.UUID:\n  type: string\n  format: uuid\n" +
+		"time.Duration:\n  type: string\n  example: 1h30m\n")
+	a.NoError(writeError)
+
+	typeMappings, err := cmd.ResolveTypeMappings(tempFile.Name())
+	a.NoError(err)
+	a.Len(typeMappings, 2)
+	a.Equal("uuid", typeMappings["github.com/google/uuid.UUID"].Format)
+	a.Equal("1h30m", typeMappings["time.Duration"].Example)
+}
+
+func TestResolveTypeMappings_None(t *testing.T) {
+	a := assert.New(t)
+
+	typeMappings, err := cmd.ResolveTypeMappings("")
+	a.NoError(err)
+	a.Nil(typeMappings)
+}
+
 func TestResolveOutputSink_YAML(t *testing.T) {
 	a := assert.New(t)
 
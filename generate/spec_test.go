@@ -38,6 +38,29 @@ func TestYAMLSink(t *testing.T) {
 	a.Equal("key: value\n", string(writtenContent))
 }
 
+func TestSinks_Extensions(t *testing.T) {
+	a := assert.New(t)
+	root := &models.Root{
+		OpenAPI:    "3.0.2",
+		Info:       &models.Info{Title: "title", Version: "1.0"},
+		Extensions: models.Extensions{"x-internal": true},
+	}
+
+	tempJSON, tempFileError := ioutil.TempFile("", "*.json")
+	a.NoError(tempFileError)
+	a.NoError((&generate.JSONSink{W: tempJSON}).Write(root))
+	writtenContent, readError := ioutil.ReadFile(tempJSON.Name())
+	a.NoError(readError)
+	a.Contains(string(writtenContent), "\n  \"x-internal\": true\n}")
+
+	tempYAML, tempFileError := ioutil.TempFile("", "*.yaml")
+	a.NoError(tempFileError)
+	a.NoError((&generate.YAMLSink{W: tempYAML}).Write(root))
+	writtenContent, readError = ioutil.ReadFile(tempYAML.Name())
+	a.NoError(readError)
+	a.Contains(string(writtenContent), "\nx-internal: true\n")
+}
+
 func TestGoFileVisitor(t *testing.T) {
 	a := assert.New(t)
 	tempDir, tempDirError := ioutil.TempDir("", "some-dir")
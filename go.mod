@@ -1,9 +1,20 @@
 module github.com/VanMoof/gopenapi
 
-go 1.13
+go 1.22.0
 
 require (
 	github.com/spf13/cobra v0.0.5
 	github.com/stretchr/testify v1.4.0
-	gopkg.in/yaml.v3 v3.0.0-20190905181640-827449938966
+	golang.org/x/tools v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/inconshreveable/mousetrap v1.0.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/spf13/pflag v1.0.3 // indirect
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
 )
@@ -0,0 +1,21 @@
+// +build testResource
+
+package _invalid_test_files
+
+/*
+gopenapi:operation GET /orders
+responses:
+  200:
+    description: The orders
+*/
+func listOrders() {
+}
+
+/*
+gopenapi:operation GET /orders
+responses:
+  200:
+    description: The orders, again
+*/
+func listOrdersAgain() {
+}
@@ -0,0 +1,23 @@
+// +build testResource
+
+package _invalid_test_files
+
+/*
+gopenapi:operation GET /items
+responses:
+  200:
+    description: The items
+*/
+func listItems() {
+}
+
+/*
+gopenapi:path
+/items:
+  get:
+    responses:
+      200:
+        description: The items, again
+*/
+func listItemsAgain() {
+}
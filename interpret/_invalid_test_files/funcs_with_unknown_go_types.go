@@ -0,0 +1,16 @@
+// +build testResource
+
+package _invalid_test_files
+
+/*
+gopenapi:operation GET /missing
+responses:
+  200:
+    description: The missing model
+    content:
+      application/json:
+        schema:
+          goType: MissingModel
+*/
+func getMissing() {
+}
@@ -0,0 +1,9 @@
+// +build testResource
+
+package _invalid_test_files
+
+/*
+gopenapi:header
+required: [true
+*/
+const InvalidHeader = "Invalid-Header"
@@ -0,0 +1,8 @@
+// +build testResource
+
+package order
+
+//gopenapi:objectSchema
+type Response struct {
+	OrderID int64 `json:"orderId"`
+}
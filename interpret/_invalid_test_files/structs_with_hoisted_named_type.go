@@ -0,0 +1,13 @@
+// +build testResource
+
+package _invalid_test_files
+
+//gopenapi:objectSchema
+type Listing struct {
+	//gopenapi:objectSchema
+	Page Page `json:"page"`
+}
+
+type Page struct {
+	Number int `json:"number"`
+}
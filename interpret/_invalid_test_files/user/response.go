@@ -0,0 +1,8 @@
+// +build testResource
+
+package user
+
+//gopenapi:objectSchema
+type Response struct {
+	UserID int64 `json:"userId"`
+}
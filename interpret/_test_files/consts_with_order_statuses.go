@@ -0,0 +1,5 @@
+// +build testResource
+
+package _test_files
+
+const StatusShipped OrderStatus = "shipped"
@@ -0,0 +1,66 @@
+// +build testResource
+
+package _test_files
+
+import "github.com/VanMoof/gopenapi/interpret/_test_files/shared"
+
+/*
+gopenapi:operation GET /shops
+responses:
+  200:
+    description: The shops
+    content:
+      application/json:
+        schema:
+          type: array
+          items:
+            goType: Shop
+*/
+func listShops() {
+}
+
+/*
+gopenapi:path
+/shops/{id}/hours:
+  put:
+    requestBody:
+      content:
+        application/json:
+          schema:
+            goType: shared.Hours
+    responses:
+      200:
+        description: The addresses of the shop
+        content:
+          application/json:
+            schema:
+              goType: map[string]*Address
+*/
+func updateHours(hours shared.Hours) {
+}
+
+/*
+gopenapi:response
+description: The time of the server
+content:
+  application/json:
+    schema:
+      goType: time.Time
+*/
+var ServerTime struct{}
+
+/*
+gopenapi:response
+description: The attachment and its metadata
+content:
+  application/octet-stream:
+    schema:
+      goType: '[]byte'
+  application/json:
+    schema:
+      goType: map[string]any
+  text/plain:
+    schema:
+      goType: any
+*/
+var Attachment struct{}
@@ -0,0 +1,27 @@
+// +build testResource
+
+package _test_files
+
+/*
+gopenapi:operation GET /orders/{id}
+summary: Get an order
+x-internal: true
+parameters:
+  - name: id
+    in: path
+responses:
+	200:
+	  description: The order
+*/
+func getOrder() {
+}
+
+/*
+gopenapi:operation delete /orders/{id}
+summary: Delete an order
+responses:
+  204:
+    description: The order was deleted
+*/
+func deleteOrder() {
+}
@@ -0,0 +1,50 @@
+// +build testResource
+
+package _test_files
+
+/*
+gopenapi:path
+/health:
+  get:
+    summary: Get the health
+    responses:
+      200:
+        description: The health
+*/
+func getHealth() {
+}
+
+/*
+gopenapi:path
+/health:
+  post:
+    summary: Report the health
+    responses:
+      204:
+        description: The health was reported
+*/
+func reportHealth() {
+}
+
+/*
+gopenapi:operation GET /status
+summary: Get the status
+responses:
+  200:
+    description: The status
+*/
+func getStatus() {
+}
+
+/*
+gopenapi:path
+/status:
+  description: The status of the service
+  put:
+    summary: Set the status
+    responses:
+      204:
+        description: The status was set
+*/
+func setStatus() {
+}
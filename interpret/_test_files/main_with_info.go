@@ -15,6 +15,8 @@ contact:
 license:
   name: Apache 2.0
   url: https://www.apache.org/licenses/LICENSE-2.0.html
+security:
+  - OAuth: [read, write]
 */
 func main() {
 }
@@ -0,0 +1,39 @@
+// +build testResource
+
+package _test_files
+
+/*
+gopenapi:server
+url: https://staging.example.com/{version}
+description: Staging
+variables:
+  version:
+    default: v1
+    enum: [v1, v2]
+*/
+/*
+gopenapi:server
+url: https://example.com
+description: Production
+*/
+/*
+gopenapi:tag
+name: orders
+description: Order management
+externalDocs:
+  url: https://docs.example.com/orders
+*/
+func serve() {
+}
+
+/*
+gopenapi:server
+url: https://example.com
+description: Production, again
+*/
+/*
+gopenapi:tag
+name: orders
+description: Everything about orders
+*/
+const ServedOrders = "orders"
@@ -0,0 +1,26 @@
+// +build testResource
+
+package shared
+
+// Forwarded is an event of another system that is forwarded as it is.
+//
+//gopenapi:objectSchema
+type Forwarded struct {
+	Kind    string                 `json:"kind"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// Type returns the kind of the forwarded event
+func (f Forwarded) Type() string {
+	return f.Kind
+}
+
+// Broadcast is a message to everyone. It is no notification, since only the types of the package of Notification may
+// implement it.
+//
+//gopenapi:objectSchema
+type Broadcast struct {
+	Message string `json:"message"`
+}
+
+func (Broadcast) isNotification() {}
@@ -0,0 +1,9 @@
+// +build testResource
+
+package shared
+
+// Hours are the opening hours of a shop.
+type Hours struct {
+	Opens  string `json:"opens"`
+	Closes string `json:"closes"`
+}
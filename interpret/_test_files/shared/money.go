@@ -0,0 +1,9 @@
+// +build testResource
+
+package shared
+
+// Money is an amount in the minor unit of its currency, which is marshalled as a string like "EUR 12.34".
+type Money struct {
+	Currency string
+	Amount   int64
+}
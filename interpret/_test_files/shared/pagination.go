@@ -0,0 +1,11 @@
+// +build testResource
+
+package shared
+
+// Pagination describes the page of a listing.
+//
+//gopenapi:objectSchema
+type Pagination struct {
+	Page     int64 `json:"page" validate:"required"`
+	PageSize int64 `json:"pageSize"`
+}
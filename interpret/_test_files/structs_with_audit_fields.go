@@ -0,0 +1,13 @@
+// +build testResource
+
+package _test_files
+
+import "time"
+
+// AuditFields are embedded in the models that are audited.
+//
+//gopenapi:objectSchema
+type AuditFields struct {
+	CreatedAt time.Time `json:"createdAt" validate:"required"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
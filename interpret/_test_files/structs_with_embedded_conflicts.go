@@ -0,0 +1,52 @@
+// +build testResource
+
+package _test_files
+
+// Parent embeds a Child, which embeds its Parent in turn.
+//
+//gopenapi:objectSchema
+type Parent struct {
+	*Child
+	Name string `json:"name"`
+}
+
+type Child struct {
+	*Parent
+	Age int `json:"age"`
+}
+
+// Shipment embeds structs with fields of the same names.
+//
+//gopenapi:objectSchema
+type Shipment struct {
+	Origin
+	Destination
+	*Carrier
+	Weight int `json:"weight"`
+}
+
+type Origin struct {
+	Stamp
+	Address string `json:"address"`
+	Code    string `json:"Code"`
+}
+
+type Destination struct {
+	Stamp
+	Address string `json:"address"`
+	Code    string
+}
+
+type Stamp struct {
+	StampedAt string `json:"stampedAt"`
+}
+
+type Carrier struct {
+	Tracking
+	Name string `json:"carrier"`
+}
+
+type Tracking struct {
+	Address string `json:"address"`
+	Number  string `json:"trackingNumber"`
+}
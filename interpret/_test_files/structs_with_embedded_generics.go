@@ -0,0 +1,32 @@
+// +build testResource
+
+package _test_files
+
+import "net/http"
+
+type Revision[T any] struct {
+	Value    T   `json:"value"`
+	Revision int `json:"revision"`
+}
+
+type Labelled[K comparable, V any] struct {
+	Label  K   `json:"label"`
+	Values []V `json:"values"`
+}
+
+//gopenapi:objectSchema
+type RevisedCounter struct {
+	Revision[int]
+	Name string `json:"name"`
+}
+
+//gopenapi:objectSchema
+type LabelledMeasurements struct {
+	*Labelled[string, float64]
+}
+
+//gopenapi:objectSchema
+type ScopedCookie struct {
+	http.Cookie
+	Scope string `json:"scope"`
+}
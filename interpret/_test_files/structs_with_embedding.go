@@ -0,0 +1,35 @@
+// +build testResource
+
+package _test_files
+
+import (
+	"github.com/VanMoof/gopenapi/interpret/_test_files/shared"
+	"time"
+)
+
+// BikeListing is a page of bikes.
+//
+//gopenapi:objectSchema
+type BikeListing struct {
+	shared.Pagination
+	*Bikes
+}
+
+//gopenapi:objectSchema
+type Bikes struct {
+	AuditFields
+	Items []*Bike `json:"items"`
+}
+
+//gopenapi:objectSchema
+type Bike struct {
+	*AuditFields
+	Owner     `json:"owner"`
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updatedAt" validate:"required"`
+}
+
+//gopenapi:objectSchema
+type Owner struct {
+	Name string `json:"name"`
+}
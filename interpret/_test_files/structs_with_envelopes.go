@@ -0,0 +1,28 @@
+// +build testResource
+
+package _test_files
+
+//gopenapi:objectSchema
+type ItemsEnvelope struct {
+	Data []*SubModel `json:"data"`
+	// The metadata of the page
+	Meta struct {
+		Total int `json:"total"`
+		Links *struct {
+			Next string `json:"next,omitempty"`
+		} `json:"links"`
+	} `json:"meta"`
+	Errors []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors,omitempty"`
+	// The cursor of the next page
+	//gopenapi:objectSchema
+	Cursor *struct {
+		After string `json:"after"`
+	} `json:"cursor"`
+	//gopenapi:objectSchema itemsFilter
+	Filter struct {
+		Labels map[string]struct{} `json:"labels"`
+	} `json:"filter"`
+}
@@ -0,0 +1,136 @@
+// +build testResource
+
+package _test_files
+
+const (
+	// EventTypeCreated is the type of the events about created resources
+	EventTypeCreated = "created"
+	// EventTypeDeleted is the type of the events about deleted resources
+	EventTypeDeleted = "deleted"
+)
+
+// Event is something that happened to a resource
+//
+//gopenapi:objectSchema
+type Event interface {
+	// Type returns the type of the event, which is written as its kind
+	//
+	//gopenapi:discriminator kind
+	Type() string
+}
+
+// ResourceCreated is the event of a created resource
+//
+//gopenapi:objectSchema
+type ResourceCreated struct {
+	Kind     string `json:"kind"`
+	Resource string `json:"resource"`
+}
+
+func (ResourceCreated) Type() string {
+	return EventTypeCreated
+}
+
+// ResourceDeleted is the event of a deleted resource
+//
+//gopenapi:objectSchema
+type ResourceDeleted struct {
+	Kind     string `json:"kind"`
+	Resource string `json:"resource"`
+}
+
+func (*ResourceDeleted) Type() string {
+	return EventTypeDeleted
+}
+
+// ResourceRenamed is the event of a renamed resource
+//
+//gopenapi:objectSchema
+type ResourceRenamed struct {
+	Kind string `json:"kind"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (r ResourceRenamed) Type() string {
+	return "renamed"
+}
+
+// ResourceArchived is the event of an archived resource, of which the kind may be left out
+//
+//gopenapi:objectSchema
+type ResourceArchived struct {
+	Kind     string `json:"kind,omitempty"`
+	Resource string `json:"resource"`
+}
+
+func (ResourceArchived) Type() string {
+	return "archived"
+}
+
+// ResourcePurged is the event of a purged resource, which lacks a kind
+//
+//gopenapi:objectSchema
+type ResourcePurged struct {
+	Resource string `json:"resource"`
+}
+
+func (ResourcePurged) Type() string {
+	return "purged"
+}
+
+// Task is no event, since its Type method returns a number
+//
+//gopenapi:objectSchema
+type Task struct {
+	Kind int `json:"kind"`
+}
+
+func (Task) Type() int {
+	return 1
+}
+
+// resourceTouched is an event too, but it is left out since it is not annotated
+type resourceTouched struct{}
+
+func (resourceTouched) Type() string {
+	return "touched"
+}
+
+// EventLog is a log of events
+//
+//gopenapi:objectSchema
+type EventLog struct {
+	Events []Event `json:"events"`
+}
+
+/*
+Notification is sent to a user over one of the channels.
+
+gopenapi:objectSchema
+discriminator:
+  propertyName: channel
+*/
+type Notification interface {
+	isNotification()
+}
+
+// EmailNotification is a notification sent by email
+//
+//gopenapi:objectSchema
+type EmailNotification struct {
+	Channel string `json:"channel"`
+	Address string `json:"address"`
+}
+
+func (EmailNotification) isNotification() {}
+
+// PushNotification is a notification pushed to a device
+//
+//gopenapi:objectSchema
+type PushNotification struct {
+	Channel string `json:"channel"`
+	Device  string `json:"device"`
+}
+
+func (PushNotification) isNotification() {}
@@ -0,0 +1,29 @@
+// +build testResource
+
+package _test_files
+
+//gopenapi:objectSchema
+type Listing struct {
+	ID       string  `json:"id" openapi:"readOnly,format=uuid"`
+	Password string  `json:"password" openapi:"writeOnly"`
+	Limit    int     `json:"limit,omitempty" openapi:"example=42,default=10"`
+	Ratio    float64 `json:"ratio,omitempty" openapi:"default=0.5"`
+	Order    string  `json:"order,omitempty" openapi:"default=asc,description=The order of the items"`
+	Legacy   bool    `json:"legacy,omitempty" openapi:"deprecated,optional,default=false"`
+	Sort     string  `json:"sort,omitempty" openapi:"description='The sort, ascending',example='name,price',colour=red"`
+	// The labels of the listing
+	/*
+		gopenapi:field
+		example:
+		  - new
+		  - sale
+		items:
+		  minLength: 1
+	*/
+	Labels []string `json:"labels"`
+	// The price of the listing
+	//
+	//gopenapi:field
+	//example: 12.5
+	Price *float64 `json:"price" openapi:"readOnly"`
+}
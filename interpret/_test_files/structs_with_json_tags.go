@@ -0,0 +1,29 @@
+// +build testResource
+
+package _test_files
+
+type Code string
+
+type name string
+
+//gopenapi:objectSchema
+type Tier int
+
+//gopenapi:objectSchema
+type Account struct {
+	Code
+	name
+	ID          int64   `json:",string"`
+	Balance     float64 `json:"balance,string"`
+	Active      *bool   `json:"active,string,omitempty"`
+	Nickname    string  `json:"nickname,string"`
+	Quantity    int     `json:"quantity,string" validate:"min=1,oneof=1 2 3"`
+	Tier        Tier    `json:"tier,string"`
+	Email       string  `json:",omitempty"`
+	Phone       string  `validate:"required"`
+	Dash        string  `json:"-,"`
+	Hidden      string  `json:"-"`
+	Weird       string  `json:"we\"ird"`
+	internal    string
+	First, Last string
+}
@@ -0,0 +1,21 @@
+// +build testResource
+
+package _test_files
+
+import (
+	"encoding/json"
+	"github.com/VanMoof/gopenapi/interpret/_test_files/shared"
+	"net/netip"
+	"time"
+)
+
+//gopenapi:objectSchema
+type Invoice struct {
+	// The price of the invoice
+	Total    shared.Money    `json:"total"`
+	Refunded *shared.Money   `json:"refunded"`
+	Items    []*shared.Money `json:"items"`
+	Due      time.Duration   `json:"due"`
+	Payload  json.RawMessage `json:"payload"`
+	Issuer   netip.Addr      `json:"issuer"`
+}
@@ -0,0 +1,54 @@
+// +build testResource
+
+package _test_files
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Color is written as its hexadecimal notation, like #ff0000
+type Color struct {
+	Red   uint8
+	Green uint8
+	Blue  uint8
+}
+
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("#%02x%02x%02x", c.Red, c.Green, c.Blue)), nil
+}
+
+/*
+Coordinates are the longitude and the latitude of a location.
+
+gopenapi:objectSchema
+type: array
+items:
+  type: number
+minItems: 2
+maxItems: 2
+*/
+type Coordinates struct {
+	Longitude float64
+	Latitude  float64
+}
+
+func (c Coordinates) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]float64{c.Longitude, c.Latitude})
+}
+
+// Secret is written however its provider sees fit
+type Secret struct {
+	Value string
+}
+
+func (s *Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Value)
+}
+
+//gopenapi:objectSchema
+type Store struct {
+	Color    Color       `json:"color"`
+	Location Coordinates `json:"location"`
+	Secret   *Secret     `json:"secret"`
+}
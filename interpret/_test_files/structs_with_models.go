@@ -4,16 +4,25 @@ package _test_files
 
 import "time"
 
+// RootModel is the model at the root.
+// It holds the sub models.
+//
 //gopenapi:objectSchema
 type RootModel struct {
+	// IntField is an integer
 	IntField    int64       `json:"intField"`
-	StringField string      `json:"stringField"`
+	StringField string      `json:"stringField"` // StringField is a string
 	SubModels   []*SubModel `json:"subModels"`
 }
 
+// SubModel is a model nested in the root.
+//
+// Deprecated: use SubSubModel
+//
 //gopenapi:objectSchema
 type SubModel struct {
-	FloatField  float64                 `json:"floatField"`
+	FloatField float64 `json:"floatField"`
+	// Deprecated: use a bool
 	SubSubModel map[string]*SubSubModel `json:"subSubModel"`
 }
 
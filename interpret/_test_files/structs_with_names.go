@@ -0,0 +1,13 @@
+// +build testResource
+
+package _test_files
+
+//gopenapi:objectSchema Schedule
+type OpeningTimes struct {
+	Days []*OpeningDay `json:"days"`
+}
+
+//gopenapi:objectSchema Day
+type OpeningDay struct {
+	Weekday string `json:"weekday"`
+}
@@ -0,0 +1,21 @@
+// +build testResource
+
+package _test_files
+
+import (
+	"database/sql"
+	"time"
+)
+
+//gopenapi:objectSchema
+type Customer struct {
+	ID        int64          `json:"id"`
+	Name      string         `json:"name,omitempty"`
+	Referrer  *Customer      `json:"referrer"`
+	Nickname  sql.NullString `json:"nickname"`
+	Score     sql.NullInt64  `json:"score"`
+	Email     *string        `json:"email" openapi:"required,notnull"`
+	Street    string         `json:"street" openapi:"optional"`
+	DeletedAt time.Time      `json:"deletedAt,omitempty" openapi:"nullable"`
+	Phone     string         `json:"phone,omitempty" validate:"required"`
+}
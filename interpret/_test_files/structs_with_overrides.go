@@ -0,0 +1,48 @@
+// +build testResource
+
+package _test_files
+
+import "github.com/VanMoof/gopenapi/interpret/_test_files/shared"
+
+/*
+Vehicle is a bike or a scooter.
+
+gopenapi:objectSchema
+name: vehicleResource
+title: Vehicle
+example:
+  kind: bike
+  frame: 42
+discriminator:
+  propertyName: kind
+required:
+  - kind
+  - frame
+properties:
+  frame:
+    description: The number of the frame
+    minimum: 1
+  opening:
+    goType: shared.Hours
+x-internal: true
+*/
+type Vehicle struct {
+	Kind    string `json:"kind"`
+	Frame   int    `json:"frame,omitempty"`
+	Opening string `json:"opening"`
+}
+
+//gopenapi:objectSchema
+type Fleet struct {
+	Vehicles []*Vehicle       `json:"vehicles"`
+	Page     shared.Pagination `json:"page"`
+	/*
+		gopenapi:objectSchema fleetOwner
+		description: The owner of the fleet
+		required:
+		  - name
+	*/
+	Owner struct {
+		Name string `json:"name,omitempty"`
+	} `json:"owner"`
+}
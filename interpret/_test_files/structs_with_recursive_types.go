@@ -0,0 +1,17 @@
+// +build testResource
+
+package _test_files
+
+// Tree is a tree of names.
+type Tree map[string]Tree
+
+// Trail is a trail that branches into other trails.
+type Trail []Trail
+
+// Forest holds types that refer to themselves.
+//
+//gopenapi:objectSchema
+type Forest struct {
+	Trees  Tree  `json:"trees"`
+	Trails Trail `json:"trails"`
+}
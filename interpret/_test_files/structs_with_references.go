@@ -0,0 +1,29 @@
+// +build testResource
+
+package _test_files
+
+import (
+	"net/url"
+
+	"github.com/VanMoof/gopenapi/interpret/_test_files/shared"
+)
+
+//gopenapi:objectSchema
+type Shop struct {
+	Address  Address      `json:"address"`
+	Category *Category    `json:"category"`
+	Opening  shared.Hours `json:"opening"`
+	Website  url.URL      `json:"website"`
+}
+
+// Address is where a shop is located.
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type Category struct {
+	Name     string      `json:"name"`
+	Parent   *Category   `json:"parent"`
+	Children []*Category `json:"children"`
+}
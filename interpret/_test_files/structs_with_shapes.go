@@ -0,0 +1,25 @@
+// +build testResource
+
+package _test_files
+
+//gopenapi:objectSchema
+type Shapes struct {
+	Names    []string          `json:"names"`
+	Counts   []int             `json:"counts"`
+	Labels   map[string]string `json:"labels"`
+	Grid     [][]*SubModel     `json:"grid"`
+	Digest   [4]byte           `json:"digest"`
+	Blob     []byte            `json:"blob"`
+	Anything interface{}       `json:"anything"`
+	Payload  any               `json:"payload"`
+	Updates  chan string       `json:"updates"`
+	Callback func() error      `json:"callback"`
+	Width, Height int
+}
+
+// Matrix is a grid of numbers
+//gopenapi:objectSchema
+type Matrix [][]float64
+
+//gopenapi:objectSchema
+type Listener func(string)
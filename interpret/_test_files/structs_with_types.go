@@ -0,0 +1,25 @@
+// +build testResource
+
+package _test_files
+
+import (
+	"github.com/VanMoof/gopenapi/interpret/_test_files/shared"
+	"time"
+)
+
+// Celsius is not annotated, so the type checker replaces it with the type it is based on
+type Celsius float64
+
+type Label = string
+
+//gopenapi:objectSchema
+type Measurement struct {
+	Temperature Celsius              `json:"temperature"`
+	Readings    []*Celsius           `json:"readings"`
+	Counts      map[string]*Celsius  `json:"counts"`
+	Label       Label                `json:"label"`
+	Status      OrderStatus          `json:"status"`
+	Page        shared.Pagination    `json:"page"`
+	Taken       time.Time            `json:"taken"`
+	Owners      map[string]*Customer `json:"owners"`
+}
@@ -0,0 +1,17 @@
+// +build testResource
+
+package _test_files
+
+//gopenapi:objectSchema
+type ValidatedModel struct {
+	Name     string               `json:"name" validate:"required,min=1,max=64"`
+	Email    string               `json:"email" binding:"required,email"`
+	Kind     string               `json:"kind" validate:"oneof=a b 'c d'"`
+	Level    int                  `json:"level" validate:"omitempty,oneof=1 2 3"`
+	Age      int64                `json:"age" validate:"gte=18,lt=130"`
+	Code     string               `json:"code" validate:"len=4,alphanum"`
+	Prefixed string               `json:"prefixed" validate:"startswith=a.b"`
+	Tags     []*SubModel          `json:"tags" validate:"min=1,max=10,dive,required"`
+	Mapped   map[string]*SubModel `json:"mapped" validate:"required,dive,keys,min=2,endkeys,required"`
+	Either   string               `json:"either" validate:"email|url"`
+}
@@ -0,0 +1,21 @@
+// +build testResource
+
+package _test_files
+
+import "encoding/xml"
+
+type Timestamps struct {
+	CreatedAt string `json:"createdAt" yaml:"created_at" xml:"created"`
+}
+
+//gopenapi:objectSchema
+type Book struct {
+	XMLName    xml.Name `json:"-" yaml:"-" xml:"http://example.com/books book"`
+	Timestamps `yaml:",inline"`
+	ID         string   `json:"id" yaml:"id" xml:"id,attr"`
+	Title      string   `json:"title" yaml:"book_title" xml:"title"`
+	Authors    []string `json:"authors" yaml:"authors,omitempty" xml:"authors>author"`
+	Tags       []string `json:"tags" yaml:"tags" xml:"tag"`
+	Publisher  string   `json:"publisher" xml:"http://example.com/publishers publisher"`
+	Internal   string   `json:"internal" yaml:"-" xml:"-"`
+}
@@ -0,0 +1,27 @@
+// +build testResource
+
+package _test_files
+
+// OrderStatus is the status of an order.
+//
+//gopenapi:objectSchema
+type OrderStatus string
+
+const (
+	// StatusPending is an order that awaits payment
+	StatusPending OrderStatus = "pending"
+	StatusPaid    OrderStatus = "paid" // StatusPaid is an order that has been paid
+	StatusUnknown             = "unknown"
+)
+
+//gopenapi:objectSchema
+type Priority int
+
+const (
+	_ Priority = iota
+	PriorityLow
+	PriorityHigh
+	PriorityUrgent Priority = 1 << iota
+)
+
+const PriorityNone = Priority(-1)
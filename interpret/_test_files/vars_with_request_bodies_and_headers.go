@@ -0,0 +1,27 @@
+// +build testResource
+
+package _test_files
+
+/*
+gopenapi:requestBody
+description: A JSON upload
+required: true
+content:
+  application/json:
+    example: '{"key":"value"}'
+*/
+type JSONUpload struct {
+}
+
+/*
+gopenapi:header
+description: A key that makes the request idempotent
+required: true
+schema:
+  type: string
+  title: Idempotency key
+  minLength: 8
+  not:
+    enum: ["00000000"]
+*/
+const IdempotencyKey = "Idempotency-Key"
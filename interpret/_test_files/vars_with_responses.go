@@ -0,0 +1,19 @@
+// +build testResource
+
+package _test_files
+
+/*
+gopenapi:response
+description: The resource could not be found
+content:
+  application/json:
+    example: not found
+*/
+const NotFound = "notFound"
+
+/*
+gopenapi:response
+description: Something went wrong
+*/
+type InternalServerError struct {
+}
@@ -0,0 +1,43 @@
+// +build testResource
+
+package _test_files
+
+/*
+gopenapi:securityScheme
+type: oauth2
+description: OAuth2 authentication
+flows:
+  implicit:
+    authorizationUrl: https://example.com/oauth/authorize
+    scopes:
+      read: Read access
+      write: Write access
+*/
+const OAuth = "oauth"
+
+/*
+gopenapi:securityScheme
+type: apiKey
+name: X-API-Key
+in: header
+*/
+const APIKey = "apiKey"
+
+/*
+gopenapi:path
+/secured:
+  get:
+    security:
+      - OAuth: [read]
+      - APIKey: []
+    responses:
+      200:
+        description: A secured response
+  post:
+    security: []
+    responses:
+      200:
+        description: A public response
+*/
+func securedFunc() {
+}
@@ -0,0 +1,327 @@
+package interpret
+
+import (
+	"fmt"
+	"github.com/VanMoof/gopenapi/models"
+	"go/ast"
+	"go/token"
+	"gopkg.in/yaml.v3"
+	"io"
+	"sort"
+	"strings"
+)
+
+// annotation is a single "gopenapi:..." block of a comment
+type annotation struct {
+	// keyword is the type of the annotation, e.g. gopenapi:path
+	keyword string
+	// arguments are the words that follow the keyword on the same line
+	arguments []string
+	// content is the YAML that follows the line of the keyword
+	content string
+	// lines are the lines of the comment from the one of the keyword until the next annotation, and positions are
+	// where they begin in the file
+	lines     []string
+	positions []token.Pos
+}
+
+func (a *annotation) decode(modelPointer interface{}) error {
+	err := yaml.NewDecoder(strings.NewReader(a.content)).Decode(modelPointer)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode comment:\n%s\nError: %w", a.content, err)
+	}
+	return nil
+}
+
+// annotations splits a comment into its annotations. Each annotation begins at a line that starts with "gopenapi:" and
+// continues until the next one. Any text preceding the first annotation is not part of an annotation
+func annotations(comment string) []*annotation {
+	var found []*annotation
+	var contentLines []string
+	flush := func() {
+		if len(found) != 0 {
+			found[len(found)-1].content = cleanComment(strings.Join(dedent(contentLines), "\n"))
+		}
+		contentLines = nil
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmedLine, "gopenapi:") {
+			contentLines = append(contentLines, line)
+			continue
+		}
+		flush()
+		words := strings.Fields(trimmedLine)
+		found = append(found, &annotation{keyword: words[0], arguments: words[1:]})
+	}
+	flush()
+	return found
+}
+
+// annotationsOf returns the annotations of the doc comment along with the lines of the comment that they span
+func annotationsOf(doc *ast.CommentGroup) []*annotation {
+	found := annotations(commentText(doc))
+	if doc == nil {
+		return found
+	}
+	n := -1
+	for _, comment := range doc.List {
+		offset := 0
+		for _, line := range strings.Split(comment.Text, "\n") {
+			position := comment.Slash + token.Pos(offset)
+			offset += len(line) + 1
+			text := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "//"), "/*"))
+			if strings.HasPrefix(text, "gopenapi:") {
+				n++
+			}
+			if n < 0 || n >= len(found) {
+				continue
+			}
+			found[n].lines = append(found[n].lines, line)
+			found[n].positions = append(found[n].positions, position)
+		}
+	}
+	return found
+}
+
+// position returns the position of the first line of the annotation that contains all the texts, or of the
+// annotation itself when none does
+func (a *annotation) position(texts ...string) token.Pos {
+	for n, line := range a.lines {
+		if containsAll(line, texts) {
+			return a.positions[n]
+		}
+	}
+	if len(a.positions) == 0 {
+		return token.NoPos
+	}
+	return a.positions[0]
+}
+
+func containsAll(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if !strings.Contains(s, substring) {
+			return false
+		}
+	}
+	return true
+}
+
+// prose returns the text of a comment that precedes its first annotation
+func prose(comment string) string {
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "gopenapi:") {
+			lines = lines[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// commentAsOpenAPIBlock resolves an annotation of an element of the root, and returns the element that it decoded
+func commentAsOpenAPIBlock(r *models.Root, a *annotation) (interface{}, error) {
+	switch a.keyword {
+	case "gopenapi:info":
+		err := a.decode(&infoBlock{root: r})
+		return r.Info, err
+	case "gopenapi:path":
+		// The paths are decoded on their own, so that only their goTypes are resolved
+		paths := map[string]*models.PathItem{}
+		err := a.decode(&paths)
+		if err != nil {
+			return nil, err
+		}
+		sortedPaths := make([]string, 0, len(paths))
+		for path := range paths {
+			sortedPaths = append(sortedPaths, path)
+		}
+		sort.Strings(sortedPaths)
+		for _, path := range sortedPaths {
+			if err := r.Paths.AddPathItem(path, paths[path]); err != nil {
+				return nil, err
+			}
+		}
+		return paths, nil
+	case "gopenapi:server":
+		block := &serverBlock{root: r}
+		err := a.decode(block)
+		return block.server, err
+	case "gopenapi:tag":
+		block := &tagBlock{root: r}
+		err := a.decode(block)
+		return block.tag, err
+	}
+	return nil, nil
+}
+
+// infoBlock decodes a gopenapi:info annotation, which besides the Info element may contain the security requirements
+// that apply to the whole API
+type infoBlock struct {
+	root *models.Root
+}
+
+func (i *infoBlock) UnmarshalYAML(value *yaml.Node) error {
+	info := &models.Info{}
+	if err := value.Decode(info); err != nil {
+		return err
+	}
+	decoded := struct {
+		Security []models.SecurityRequirement `yaml:"security"`
+	}{}
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	i.root.Info = info
+	i.root.Security = append(i.root.Security, decoded.Security...)
+	return nil
+}
+
+// serverBlock decodes a gopenapi:server annotation and appends it to the servers of the root. A server with the same
+// URL as an existing one replaces it
+type serverBlock struct {
+	root   *models.Root
+	server *models.Server
+}
+
+func (s *serverBlock) UnmarshalYAML(value *yaml.Node) error {
+	server := &models.Server{}
+	if err := value.Decode(server); err != nil {
+		return err
+	}
+	s.server = server
+	for i, existingServer := range s.root.Servers {
+		if existingServer.URL == server.URL {
+			s.root.Servers[i] = server
+			return nil
+		}
+	}
+	s.root.Servers = append(s.root.Servers, server)
+	return nil
+}
+
+// tagBlock decodes a gopenapi:tag annotation and appends it to the tags of the root. A tag with the same name as an
+// existing one replaces it
+type tagBlock struct {
+	root *models.Root
+	tag  *models.Tag
+}
+
+func (t *tagBlock) UnmarshalYAML(value *yaml.Node) error {
+	tag := &models.Tag{}
+	if err := value.Decode(tag); err != nil {
+		return err
+	}
+	t.tag = tag
+	for i, existingTag := range t.root.Tags {
+		if existingTag.Name == tag.Name {
+			t.root.Tags[i] = tag
+			return nil
+		}
+	}
+	t.root.Tags = append(t.root.Tags, tag)
+	return nil
+}
+
+// componentTypes maps the annotations of reusable components to a function that registers a new, empty component
+// under the given name and returns a pointer to it
+var componentTypes = map[string]func(c *models.Components, name string) interface{}{
+	"gopenapi:response": func(c *models.Components, name string) interface{} {
+		if c.Responses == nil {
+			c.Responses = map[string]*models.Response{}
+		}
+		response := &models.Response{}
+		c.Responses[name] = response
+		return response
+	},
+	"gopenapi:requestBody": func(c *models.Components, name string) interface{} {
+		if c.RequestBodies == nil {
+			c.RequestBodies = map[string]*models.RequestBody{}
+		}
+		requestBody := &models.RequestBody{}
+		c.RequestBodies[name] = requestBody
+		return requestBody
+	},
+	"gopenapi:header": func(c *models.Components, name string) interface{} {
+		if c.Headers == nil {
+			c.Headers = map[string]*models.Header{}
+		}
+		header := &models.Header{}
+		c.Headers[name] = header
+		return header
+	},
+	"gopenapi:securityScheme": func(c *models.Components, name string) interface{} {
+		if c.SecuritySchemes == nil {
+			c.SecuritySchemes = map[string]*models.SecurityScheme{}
+		}
+		securityScheme := &models.SecurityScheme{}
+		c.SecuritySchemes[name] = securityScheme
+		return securityScheme
+	},
+}
+
+// commentAsDeclarationBlock resolves an annotation of a const, var or type declaration. Reusable components are
+// registered under the identifier of the declaration, any other annotation is resolved as an element of the root. It
+// returns the element that it decoded
+func commentAsDeclarationBlock(r *models.Root, name string, a *annotation) (interface{}, error) {
+	componentRegisterer, ok := componentTypes[a.keyword]
+	if !ok {
+		return commentAsOpenAPIBlock(r, a)
+	}
+	if r.Components == nil {
+		r.Components = &models.Components{}
+	}
+	component := componentRegisterer(r.Components, name)
+	err := a.decode(component)
+	if err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+// commentText returns the text of the comment group like ast.CommentGroup.Text does, but keeps the lines written as
+// "//gopenapi:..." which the ast package considers to be directives and drops
+func commentText(commentGroup *ast.CommentGroup) string {
+	if commentGroup == nil {
+		return ""
+	}
+	comments := make([]*ast.Comment, len(commentGroup.List))
+	for i, comment := range commentGroup.List {
+		comments[i] = &ast.Comment{Slash: comment.Slash, Text: comment.Text}
+		if strings.HasPrefix(comment.Text, "//gopenapi:") {
+			comments[i].Text = "// " + strings.TrimPrefix(comment.Text, "//")
+		}
+	}
+	return (&ast.CommentGroup{List: comments}).Text()
+}
+
+// dedent removes the indentation that all lines have in common, like the one of a block comment on a struct field
+func dedent(lines []string) []string {
+	indentation := ""
+	indented := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lineIndentation := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if !indented {
+			indentation = lineIndentation
+			indented = true
+		}
+		for !strings.HasPrefix(lineIndentation, indentation) {
+			indentation = indentation[:len(indentation)-1]
+		}
+	}
+	dedented := make([]string, len(lines))
+	for i, line := range lines {
+		dedented[i] = strings.TrimPrefix(line, indentation)
+	}
+	return dedented
+}
+
+func cleanComment(c string) string {
+	return strings.ReplaceAll(strings.TrimSpace(c), "\t", "    ")
+}
@@ -0,0 +1,226 @@
+package interpret
+
+import (
+	"github.com/VanMoof/gopenapi/models"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// enumFromConstants sets the constants of the named type that are declared in the package as the enum of the schema.
+// The doc comments of the constants become the x-enum-descriptions of the schema
+func (i *interpretation) enumFromConstants(typeName string, schema *models.Schema) {
+	var descriptions []interface{}
+	hasDescriptions := false
+	i.pkg.eachConstant(func(c *packageConstant) bool {
+		var value constant.Value
+		ok := false
+		if i.pkg.typesInfo != nil {
+			value, ok = i.pkg.typedConstantValue(c.name, typeName)
+		} else if c.valueExpr != nil && isOfNamedType(c.typeExpr, c.valueExpr, typeName) {
+			value, ok = constantValue(c.valueExpr, c.iota)
+		}
+		if !ok {
+			return true
+		}
+		schema.Enum = append(schema.Enum, enumValue(schema, value))
+		description := prose(commentText(valueSpecDoc(c.genDecl, c.valueSpec)))
+		hasDescriptions = hasDescriptions || description != ""
+		descriptions = append(descriptions, description)
+		return true
+	})
+	if hasDescriptions {
+		if schema.Extensions == nil {
+			schema.Extensions = models.Extensions{}
+		}
+		schema.Extensions["x-enum-descriptions"] = descriptions
+	}
+}
+
+// packageConstant is a constant that is declared in a package. A constant without a type and values repeats the ones of
+// the previous constant of its block, so its type and value expressions are the repeated ones then. The value
+// expression is nil when there is none
+type packageConstant struct {
+	name      *ast.Ident
+	typeExpr  ast.Expr
+	valueExpr ast.Expr
+	iota      int64
+	genDecl   *ast.GenDecl
+	valueSpec *ast.ValueSpec
+}
+
+// eachConstant calls the function with every named constant of the package, in the order of the names of the files and
+// of the declarations in the files, until the function returns false
+func (a *astPackage) eachConstant(f func(c *packageConstant) bool) {
+	for _, file := range a.sortedFiles() {
+		for _, declaration := range file.Decls {
+			genDecl, ok := declaration.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			var specType ast.Expr
+			var specValues []ast.Expr
+			for iota, spec := range genDecl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				if valueSpec.Type != nil || len(valueSpec.Values) != 0 {
+					specType, specValues = valueSpec.Type, valueSpec.Values
+				}
+				for n, name := range valueSpec.Names {
+					if name.Name == "_" {
+						continue
+					}
+					c := &packageConstant{name: name, typeExpr: specType, iota: int64(iota), genDecl: genDecl, valueSpec: valueSpec}
+					if n < len(specValues) {
+						c.valueExpr = specValues[n]
+					}
+					if !f(c) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// sortedFiles returns the files of the package in the order of their names, so that the output does not depend on the
+// order of a map
+func (a *astPackage) sortedFiles() []*ast.File {
+	var fileNames []string
+	for fileName := range a.files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	var files []*ast.File
+	for _, fileName := range fileNames {
+		files = append(files, a.files[fileName])
+	}
+	return files
+}
+
+// typedConstantValue returns the value of the constant when the type checker resolved it to be of the named type of
+// the package
+func (a *astPackage) typedConstantValue(name *ast.Ident, typeName string) (constant.Value, bool) {
+	c, ok := a.typesInfo.Defs[name].(*types.Const)
+	if !ok {
+		return nil, false
+	}
+	named, ok := types.Unalias(c.Type()).(*types.Named)
+	if !ok || named.Obj().Name() != typeName || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != a.path {
+		return nil, false
+	}
+	return c.Val(), true
+}
+
+// valueSpecDoc returns the doc comment of a constant, falling back to its line comment
+func valueSpecDoc(genDecl *ast.GenDecl, valueSpec *ast.ValueSpec) *ast.CommentGroup {
+	doc := genDecl.Doc
+	if genDecl.Lparen.IsValid() {
+		doc = valueSpec.Doc
+	}
+	if doc == nil {
+		return valueSpec.Comment
+	}
+	return doc
+}
+
+// isOfNamedType reports whether a constant is of the named type, either by its declared type or by a conversion of its
+// value
+func isOfNamedType(typeExpr ast.Expr, valueExpr ast.Expr, typeName string) bool {
+	if typeExpr != nil {
+		ident, ok := typeExpr.(*ast.Ident)
+		return ok && ident.Name == typeName
+	}
+	callExpr, ok := valueExpr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := callExpr.Fun.(*ast.Ident)
+	return ok && ident.Name == typeName && len(callExpr.Args) == 1
+}
+
+// constantValue evaluates a constant expression made up of literals, iota and operators
+func constantValue(expr ast.Expr, iota int64) (constant.Value, bool) {
+	switch expr.(type) {
+	case *ast.BasicLit:
+		basicLit := expr.(*ast.BasicLit)
+		value := constant.MakeFromLiteral(basicLit.Value, basicLit.Kind, 0)
+		return value, value.Kind() != constant.Unknown
+	case *ast.Ident:
+		switch expr.(*ast.Ident).Name {
+		case "iota":
+			return constant.MakeInt64(iota), true
+		case "true":
+			return constant.MakeBool(true), true
+		case "false":
+			return constant.MakeBool(false), true
+		}
+	case *ast.ParenExpr:
+		return constantValue(expr.(*ast.ParenExpr).X, iota)
+	case *ast.CallExpr:
+		callExpr := expr.(*ast.CallExpr)
+		if len(callExpr.Args) == 1 {
+			return constantValue(callExpr.Args[0], iota)
+		}
+	case *ast.UnaryExpr:
+		unaryExpr := expr.(*ast.UnaryExpr)
+		x, ok := constantValue(unaryExpr.X, iota)
+		if !ok {
+			return nil, false
+		}
+		return constant.UnaryOp(unaryExpr.Op, x, 0), true
+	case *ast.BinaryExpr:
+		binaryExpr := expr.(*ast.BinaryExpr)
+		x, ok := constantValue(binaryExpr.X, iota)
+		if !ok {
+			return nil, false
+		}
+		y, ok := constantValue(binaryExpr.Y, iota)
+		if !ok {
+			return nil, false
+		}
+		switch binaryExpr.Op {
+		case token.SHL, token.SHR:
+			shift, ok := constant.Uint64Val(y)
+			if !ok {
+				return nil, false
+			}
+			return constant.Shift(x, binaryExpr.Op, uint(shift)), true
+		case token.QUO:
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				if constant.Sign(y) == 0 {
+					return nil, false
+				}
+				return constant.BinaryOp(x, token.QUO_ASSIGN, y), true
+			}
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return constant.MakeBool(constant.Compare(x, binaryExpr.Op, y)), true
+		}
+		return constant.BinaryOp(x, binaryExpr.Op, y), true
+	}
+	return nil, false
+}
+
+// enumValue converts a constant to a value of the type of the schema
+func enumValue(schema *models.Schema, value constant.Value) interface{} {
+	switch schema.Type {
+	case "integer":
+		if i, ok := constant.Int64Val(constant.ToInt(value)); ok {
+			return i
+		}
+	case "number":
+		if f, ok := constant.Float64Val(constant.ToFloat(value)); ok {
+			return f
+		}
+	case "boolean":
+		if value.Kind() == constant.Bool {
+			return constant.BoolVal(value)
+		}
+	case "string":
+		if value.Kind() == constant.String {
+			return constant.StringVal(value)
+		}
+	}
+	return value.ExactString()
+}
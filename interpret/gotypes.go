@@ -0,0 +1,80 @@
+package interpret
+
+import (
+	"fmt"
+	"github.com/VanMoof/gopenapi/models"
+	"go/ast"
+	"go/parser"
+	"reflect"
+)
+
+var schemaType = reflect.TypeOf(models.Schema{})
+
+// resolveGoTypes replaces the goType of every schema in the element that the annotation decoded with a reference to
+// the schema of that type, which is generated when it does not exist yet. The Go types are resolved in the scope of the
+// file, and an error is reported at the line of the annotation that has the goType
+func (i *interpretation) resolveGoTypes(element interface{}, a *annotation) error {
+	var schemas []*models.Schema
+	collectGoTypeSchemas(reflect.ValueOf(element), &schemas)
+	for _, schema := range schemas {
+		goType := schema.GoType
+		if goType == "" {
+			// The schema has been resolved while generating the schema of another goType
+			continue
+		}
+		schema.GoType = ""
+		typeExpr, err := parser.ParseExpr(goType)
+		if err == nil {
+			err = i.setSchemaTypeOfGoType(schema, typeExpr)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to resolve goType %s at %s: %w", goType,
+				i.pkg.fileSet.Position(a.position("goType", goType)), err)
+		}
+	}
+	return nil
+}
+
+// collectGoTypeSchemas collects the schemas that have a goType from the value and everything that it refers to
+func collectGoTypeSchemas(value reflect.Value, schemas *[]*models.Schema) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return
+		}
+		if value.Kind() == reflect.Ptr && value.Type().Elem() == schemaType {
+			schema := value.Interface().(*models.Schema)
+			if schema.GoType != "" {
+				*schemas = append(*schemas, schema)
+			}
+		}
+		collectGoTypeSchemas(value.Elem(), schemas)
+	case reflect.Struct:
+		for f := 0; f < value.NumField(); f++ {
+			if value.Type().Field(f).PkgPath == "" {
+				collectGoTypeSchemas(value.Field(f), schemas)
+			}
+		}
+	case reflect.Slice:
+		for e := 0; e < value.Len(); e++ {
+			collectGoTypeSchemas(value.Index(e), schemas)
+		}
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			collectGoTypeSchemas(value.MapIndex(key), schemas)
+		}
+	}
+}
+
+// setSchemaTypeOfGoType sets the type of the schema to the one of the type expression of a goType, like it is set to
+// the one of a field of that type. A goType is not part of the source code that the type checker resolved, and may
+// name packages that the file does not import, so its types are always derived from their names. The named types of
+// the module that it refers to are always included, and other named types fail
+func (i *interpretation) setSchemaTypeOfGoType(schema *models.Schema, typeExpr ast.Expr) error {
+	untyped := *i.pkg
+	untyped.typesInfo = nil
+	g := *i
+	g.pkg = &untyped
+	g.resolvingGoType = true
+	return g.setSchemaTypeOfExpr(schema, typeExpr)
+}
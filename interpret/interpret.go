@@ -4,252 +4,247 @@ import (
 	"fmt"
 	"github.com/VanMoof/gopenapi/models"
 	"go/ast"
-	"go/parser"
 	"go/token"
-	"gopkg.in/yaml.v3"
+	"go/types"
+	"io"
 	"os"
-	"reflect"
 	"strconv"
-	"strings"
-	"unicode"
 )
 
 type Interpreter interface {
 	InterpretFile(file *os.File, root *models.Root) error
 }
 
+// Options configure how the interpreters translate the source code into a specification
+type Options struct {
+	// EmbeddedAsAllOf composes the schema of a struct with the schemas of its embedded structs using allOf, instead of
+	// flattening the fields of the embedded structs into it like encoding/json does
+	EmbeddedAsAllOf bool
+	// IncludeReferencedTypes generates the schemas of the types of the module that are referred to by other schemas,
+	// also when they are not annotated with gopenapi:objectSchema
+	IncludeReferencedTypes bool
+	// SchemaNaming names the schemas of types, which are named in lower camel case when it is empty
+	SchemaNaming SchemaNaming
+	// NameTag is the key of the struct tags that name the properties, which is json when it is empty
+	NameTag NameTag
+	// TypeMappings map types, by their import path and name like github.com/google/uuid.UUID, to the schema of their
+	// JSON representation. They take precedence over DefaultTypeMappings and over the types that are known by default
+	TypeMappings map[string]*models.Schema
+	// Warnings receives the warnings about source code that cannot be translated faithfully, which are written to
+	// os.Stderr when it is nil
+	Warnings io.Writer
+}
+
+// ASTInterpreter interprets the syntax tree of files. The other files of their package, and the packages of the same
+// module that they import, are parsed when their declarations are needed to build a schema. Only the files that
+// satisfy their build constraints are parsed for that
 type ASTInterpreter struct {
+	Options
+	// BuildTags are the build tags that are satisfied while matching the build constraints of files
+	BuildTags []string
+
+	packages packageIndex
+	names    schemaNames
 }
 
 func (a *ASTInterpreter) InterpretFile(file *os.File, root *models.Root) error {
-	fileSet := token.NewFileSet()
+	a.packages.buildTags = a.BuildTags
+	pkg, parsedFile, err := a.packages.packageOfFile(file.Name())
+	if err != nil {
+		return fmt.Errorf("failed to interpret file %s: %w", file.Name(), err)
+	}
+	if a.names == nil {
+		a.names = schemaNames{}
+	}
+	i := &interpretation{options: &a.Options, resolver: &a.packages, names: a.names, pkg: pkg, file: parsedFile, root: root}
+	return i.interpretFile()
+}
+
+// interpretation is the interpretation of a single file of a package
+type interpretation struct {
+	options  *Options
+	resolver packageResolver
+	names    schemaNames
+	pkg      *astPackage
+	file     *ast.File
+	root     *models.Root
+	// inlinedTypes are the named types of which the underlying type is being resolved
+	inlinedTypes map[*types.TypeName]bool
+	// fieldTypes are the types of the fields of a struct by their type expressions, when they are not the ones that the
+	// type checker resolved for the expressions, like the types of the fields of an instantiated generic struct
+	fieldTypes map[ast.Expr]types.Type
+	// resolvingGoType tells that the type expression of a goType is being resolved
+	resolvingGoType bool
+}
 
-	parsedFile, parseError := parser.ParseFile(fileSet, file.Name(), file, parser.ParseComments)
-	if parseError != nil {
-		return fmt.Errorf("failed to interpret file %s: %w", file.Name(), parseError)
+// typeOf returns the type of the type expression, which the type checker resolved unless it is the one of a field of
+// which the interpretation knows the type
+func (i *interpretation) typeOf(typeExpr ast.Expr) types.Type {
+	if t, ok := i.fieldTypes[typeExpr]; ok {
+		return t
 	}
-	return interpretFile(parsedFile, root)
+	return i.pkg.typesInfo.TypeOf(typeExpr)
 }
 
-func interpretFile(parsedFile *ast.File, root *models.Root) error {
-	declarations := parsedFile.Decls
+// inDeclaration returns an interpretation of the file of the type declaration, so that the identifiers used by the
+// declaration are resolved in the scope of its own file
+func (i *interpretation) inDeclaration(declaration *typeDeclaration) *interpretation {
+	return &interpretation{
+		options:  i.options,
+		resolver: i.resolver,
+		names:    i.names,
+		pkg:      declaration.pkg,
+		file:     declaration.file,
+		root:     i.root,
+	}
+}
+
+// warn writes a warning about the source code to the writer of the options
+func (i *interpretation) warn(format string, args ...interface{}) {
+	var w io.Writer = os.Stderr
+	if i.options.Warnings != nil {
+		w = i.options.Warnings
+	}
+	fmt.Fprintf(w, "warning: "+format+"\n", args...)
+}
+
+func (i *interpretation) interpretFile() error {
+	declarations := i.file.Decls
 	for _, declaration := range declarations {
 		switch declaration.(type) {
 		case *ast.FuncDecl:
-			err := openAPIBlockFromFunctionDeclaration(declaration.(*ast.FuncDecl), root)
+			err := i.openAPIBlockFromFunctionDeclaration(declaration.(*ast.FuncDecl))
 			if err != nil {
 				return err
 			}
 		case *ast.GenDecl:
-			openAPIBlockFromGenDeclaration(declaration.(*ast.GenDecl), root)
+			err := i.openAPIBlockFromGenDeclaration(declaration.(*ast.GenDecl))
+			if err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
-func openAPIBlockFromFunctionDeclaration(funcDecl *ast.FuncDecl, root *models.Root) error {
-	commentGroup := funcDecl.Doc
-	cleanedComment := cleanComment(commentGroup.Text())
-	err := commentAsOpenAPIBlock(root, cleanedComment)
-	if err != nil {
-		return fmt.Errorf("failed to resolve comment as OpenAPI element: %w", err)
+func (i *interpretation) openAPIBlockFromFunctionDeclaration(funcDecl *ast.FuncDecl) error {
+	for _, a := range annotationsOf(funcDecl.Doc) {
+		var decoded interface{}
+		var err error
+		if a.keyword == "gopenapi:operation" {
+			decoded, err = i.operationFromFunctionDeclaration(funcDecl, a)
+		} else {
+			decoded, err = commentAsOpenAPIBlock(i.root, a)
+		}
+		if err == nil {
+			err = i.resolveGoTypes(decoded, a)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to resolve comment as OpenAPI element: %w", err)
+		}
 	}
 	return nil
 }
 
-func openAPIBlockFromGenDeclaration(genDecl *ast.GenDecl, root *models.Root) {
+func (i *interpretation) operationFromFunctionDeclaration(funcDecl *ast.FuncDecl, a *annotation) (*models.Operation, error) {
+	position := i.pkg.fileSet.Position(funcDecl.Pos())
+	if len(a.arguments) != 2 {
+		return nil, fmt.Errorf("%s of %s at %s should be followed by a method and a path", a.keyword, funcDecl.Name.Name, position)
+	}
+	operation := &models.Operation{}
+	err := a.decode(operation)
+	if err != nil {
+		return nil, err
+	}
+	err = i.root.Paths.AddOperation(a.arguments[1], a.arguments[0], operation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add the operation of %s at %s: %w", funcDecl.Name.Name, position, err)
+	}
+	return operation, nil
+}
+
+func (i *interpretation) openAPIBlockFromGenDeclaration(genDecl *ast.GenDecl) error {
 	switch genDecl.Tok {
 	case token.TYPE:
-		openAPIBlockFromTypeDeclaration(genDecl, root)
+		return i.openAPIBlockFromTypeDeclaration(genDecl)
 	case token.CONST, token.VAR:
-		openAPIBlockFromConstAndVarDeclaration(genDecl, root)
+		return i.openAPIBlockFromConstAndVarDeclaration(genDecl)
 	}
+	return nil
 }
 
-func openAPIBlockFromTypeDeclaration(decl *ast.GenDecl, root *models.Root) {
-	commentGroup := decl.Doc
-	if !strings.Contains(commentGroup.Text(), "gopenapi:objectSchema") {
-		return
+func (i *interpretation) openAPIBlockFromTypeDeclaration(decl *ast.GenDecl) error {
+	if len(decl.Specs) == 0 {
+		return nil
 	}
-	for _, spec := range decl.Specs {
-		switch spec.(type) {
-		case *ast.TypeSpec:
-			openAPIBlockFromTypeSpec(spec.(*ast.TypeSpec), root)
+	for _, a := range annotationsOf(decl.Doc) {
+		if a.keyword != "gopenapi:objectSchema" {
+			typeSpec := decl.Specs[0].(*ast.TypeSpec)
+			decoded, err := commentAsDeclarationBlock(i.root, typeSpec.Name.Name, a)
+			if err == nil {
+				err = i.resolveGoTypes(decoded, a)
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for _, spec := range decl.Specs {
+			switch spec.(type) {
+			case *ast.TypeSpec:
+				typeSpec := spec.(*ast.TypeSpec)
+				err := i.openAPIBlockFromTypeSpec(typeSpec, typeSpecDoc(decl, typeSpec))
+				if err != nil {
+					return err
+				}
+			}
 		}
 	}
+	return nil
 }
 
-func openAPIBlockFromConstAndVarDeclaration(decl *ast.GenDecl, root *models.Root) error {
-	cleanedComment := cleanComment(decl.Doc.Text())
-	if !strings.HasPrefix(cleanedComment, "gopenapi:parameter") {
+func (i *interpretation) openAPIBlockFromConstAndVarDeclaration(decl *ast.GenDecl) error {
+	if len(decl.Specs) == 0 {
 		return nil
 	}
+	valueSpec := decl.Specs[0].(*ast.ValueSpec)
+	for _, a := range annotationsOf(decl.Doc) {
+		var decoded interface{}
+		var err error
+		if a.keyword == "gopenapi:parameter" {
+			decoded, err = parameterFromValueSpec(valueSpec, a, i.root)
+		} else {
+			decoded, err = commentAsDeclarationBlock(i.root, valueSpec.Names[0].Name, a)
+		}
+		if err == nil {
+			err = i.resolveGoTypes(decoded, a)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parameterFromValueSpec(valueSpec *ast.ValueSpec, a *annotation, root *models.Root) (*models.Parameter, error) {
 	if root.Components == nil {
 		root.Components = &models.Components{}
 	}
 	if root.Components.Parameters == nil {
 		root.Components.Parameters = map[string]*models.Parameter{}
 	}
-	cleanedComment = strings.TrimPrefix(cleanedComment, "gopenapi:parameter")
-	spec := decl.Specs[0]
-	valueSpec := spec.(*ast.ValueSpec)
 	parameter := models.Parameter{}
 	root.Components.Parameters[valueSpec.Names[0].Name] = &parameter
 	basicLit := valueSpec.Values[0].(*ast.BasicLit)
 	unquoted, unquoteError := strconv.Unquote(basicLit.Value)
 	if unquoteError != nil {
-		return unquoteError
+		return nil, unquoteError
 	}
 	parameter.Name = unquoted
 
-	err := yaml.NewDecoder(strings.NewReader(cleanedComment)).Decode(&parameter)
+	err := a.decode(&parameter)
 	if err != nil {
-		return fmt.Errorf("failed to decode comment:\n%s\nError: %w", cleanedComment, err)
-	}
-	return nil
-}
-
-func openAPIBlockFromTypeSpec(typeSpec *ast.TypeSpec, root *models.Root) {
-	if root.Components == nil {
-		root.Components = &models.Components{}
-	}
-	if root.Components.Schemas == nil {
-		root.Components.Schemas = map[string]*models.Schema{}
-	}
-
-	newSchema := &models.Schema{
-		Type:       "object",
-		Properties: map[string]*models.Schema{},
-	}
-	newSchemaName := lower(typeSpec.Name.Name)
-
-	root.Components.Schemas[newSchemaName] = newSchema
-	switch typeSpec.Type.(type) {
-	case *ast.StructType:
-		structType := typeSpec.Type.(*ast.StructType)
-		schemaFieldsFromStructType(structType, newSchema)
-	case *ast.ArrayType:
-		arrayType := typeSpec.Type.(*ast.ArrayType)
-		setSchemaType(newSchema, "array")
-
-		starExpr := arrayType.Elt.(*ast.StarExpr)
-
-		newSchema.Items = &models.Schema{}
-		setSchemaType(newSchema.Items, starExpr.X.(*ast.Ident).Name)
-	}
-}
-
-func structFieldName(structField *ast.Field) string {
-	if structField.Tag == nil {
-		return lower(structField.Names[0].Name)
-	}
-	structTag := reflect.StructTag(strings.ReplaceAll(structField.Tag.Value, "`", ""))
-	fieldName := structTag.Get("json")
-	if fieldName == "-" {
-		return ""
+		return nil, err
 	}
-	return fieldName
-}
-
-func schemaFieldsFromStructType(structType *ast.StructType, newSchema *models.Schema) {
-	structFields := structType.Fields
-	for _, structField := range structFields.List {
-		fieldName := structFieldName(structField)
-		if fieldName == "" {
-			continue
-		}
-		newSchema.Properties[fieldName] = &models.Schema{}
-		structFieldType := structField.Type
-		switch structFieldType.(type) {
-		case *ast.SelectorExpr:
-			selectorExpr := structFieldType.(*ast.SelectorExpr)
-			name := fmt.Sprintf("%s.%s", selectorExpr.X.(*ast.Ident).Name, selectorExpr.Sel.Name)
-			setSchemaType(newSchema.Properties[fieldName], name)
-		case *ast.Ident:
-			setSchemaType(newSchema.Properties[fieldName], structFieldType.(*ast.Ident).Name)
-		case *ast.ArrayType:
-			setSchemaType(newSchema.Properties[fieldName], "array")
-
-			arrayType := structFieldType.(*ast.ArrayType)
-			starExpr := arrayType.Elt.(*ast.StarExpr)
-
-			newSchema.Properties[fieldName].Items = &models.Schema{}
-			setSchemaType(newSchema.Properties[fieldName].Items, starExpr.X.(*ast.Ident).Name)
-		case *ast.MapType:
-			setSchemaType(newSchema.Properties[fieldName], "object")
-			mapSchema := &models.Schema{}
-
-			mapType := structFieldType.(*ast.MapType)
-			setSchemaType(mapSchema, mapType.Value.(*ast.StarExpr).X.(*ast.Ident).Name)
-			newSchema.Properties[fieldName].AdditionalProperties = mapSchema
-		}
-	}
-}
-
-func lower(s string) string {
-	a := []rune(s)
-	a[0] = unicode.ToLower(a[0])
-	return string(a)
-}
-
-func commentAsOpenAPIBlock(r *models.Root, comment string) error {
-	types := map[string]func(*models.Root) interface{}{
-		"gopenapi:info": func(r *models.Root) interface{} {
-			r.Info = &models.Info{}
-			return r.Info
-		},
-		"gopenapi:path": func(r *models.Root) interface{} {
-			if r.Paths == nil {
-				r.Paths = map[string]*models.PathItem{}
-			}
-			return &r.Paths
-		},
-	}
-	for blockType, modelPointerRetriever := range types {
-		if strings.HasPrefix(comment, blockType) {
-			comment = strings.TrimPrefix(comment, blockType)
-			modelPointer := modelPointerRetriever(r)
-			err := yaml.NewDecoder(strings.NewReader(comment)).Decode(modelPointer)
-			if err != nil {
-				return fmt.Errorf("failed to decode comment:\n%s\nError: %w", comment, err)
-			}
-			return nil
-		}
-	}
-	return nil
-}
-
-func cleanComment(c string) string {
-	return strings.ReplaceAll(strings.TrimSpace(c), "\t", "    ")
-}
-
-func setSchemaType(schema *models.Schema, typeName string) {
-	switch typeName {
-	case "bool":
-		schema.Type = "boolean"
-	case "int64", "int":
-		schema.Type = "integer"
-		schema.Format = "int64"
-	case "int32", "time.Month":
-		schema.Type = "integer"
-		schema.Format = "int32"
-	case "float64", "float":
-		schema.Type = "number"
-		schema.Format = "double"
-	case "float32":
-		schema.Type = "number"
-		schema.Format = "float"
-	case "string":
-		schema.Type = "string"
-	case "array":
-		schema.Type = "array"
-	case "object":
-		schema.Type = "object"
-	case "time.Time":
-		schema.Type = "string"
-		schema.Format = "date-time"
-	default:
-		schema.Ref = "#/components/schemas/" + lower(typeName)
-	}
-	return
+	return &parameter, nil
 }
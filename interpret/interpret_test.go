@@ -1,6 +1,7 @@
 package interpret_test
 
 import (
+	"bytes"
 	"github.com/VanMoof/gopenapi/interpret"
 	"github.com/VanMoof/gopenapi/models"
 	"github.com/stretchr/testify/assert"
@@ -15,7 +16,7 @@ func TestASTInterpreter_Info(t *testing.T) {
 	a.NoError(openError)
 
 	root := models.Root{}
-	interpreter := &interpret.ASTInterpreter{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
 	a.NoError(interpreter.InterpretFile(file, &root))
 
 	a.Equal("1.0", root.Info.Version)
@@ -26,6 +27,7 @@ func TestASTInterpreter_Info(t *testing.T) {
 	a.Equal("jimbob@jones.com", root.Info.Contact.Email)
 	a.Equal("Apache 2.0", root.Info.License.Name)
 	a.Equal("https://www.apache.org/licenses/LICENSE-2.0.html", root.Info.License.URL)
+	a.Equal([]string{"read", "write"}, root.Security[0]["OAuth"])
 }
 
 func TestASTInterpreter_Path(t *testing.T) {
@@ -35,7 +37,7 @@ func TestASTInterpreter_Path(t *testing.T) {
 	a.NoError(openError)
 
 	root := models.Root{}
-	interpreter := &interpret.ASTInterpreter{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
 	a.NoError(interpreter.InterpretFile(file, &root))
 
 	a.Equal("The default response of \"ping\"", root.Paths["/ping"].Get.Responses["200"].Description)
@@ -49,12 +51,17 @@ func TestASTInterpreter_Models(t *testing.T) {
 	a.NoError(openError)
 
 	root := models.Root{}
-	interpreter := &interpret.ASTInterpreter{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
 	a.NoError(interpreter.InterpretFile(file, &root))
 	schemas := root.Components.Schemas
 
 	rootModel := schemas["rootModel"]
 	a.Equal("object", rootModel.Type)
+	a.Equal("RootModel is the model at the root.\nIt holds the sub models.", rootModel.Description)
+	a.False(rootModel.Deprecated)
+	a.Equal("IntField is an integer", rootModel.Properties["intField"].Description)
+	a.Equal("StringField is a string", rootModel.Properties["stringField"].Description)
+	a.Empty(rootModel.Properties["subModels"].Description)
 	a.Equal("integer", rootModel.Properties["intField"].Type)
 	a.Equal("int64", rootModel.Properties["intField"].Format)
 	a.Equal("string", rootModel.Properties["stringField"].Type)
@@ -63,6 +70,10 @@ func TestASTInterpreter_Models(t *testing.T) {
 
 	subModel := schemas["subModel"]
 	a.Equal("object", subModel.Type)
+	a.Equal("SubModel is a model nested in the root.\n\nDeprecated: use SubSubModel", subModel.Description)
+	a.True(subModel.Deprecated)
+	a.False(subModel.Properties["floatField"].Deprecated)
+	a.True(subModel.Properties["subSubModel"].Deprecated)
 	a.Equal("number", subModel.Properties["floatField"].Type)
 	a.Equal("double", subModel.Properties["floatField"].Format)
 	a.Equal("object", subModel.Properties["subSubModel"].Type)
@@ -70,6 +81,7 @@ func TestASTInterpreter_Models(t *testing.T) {
 
 	subSubModel := schemas["subSubModel"]
 	a.Equal("object", subSubModel.Type)
+	a.Empty(subSubModel.Description)
 	a.Equal("boolean", subSubModel.Properties["boolField"].Type)
 	a.Equal("#/components/schemas/aliasedSubs", subSubModel.Properties["aliased"].Ref)
 
@@ -79,8 +91,8 @@ func TestASTInterpreter_Models(t *testing.T) {
 
 	aliasedSub := schemas["aliasedSub"]
 	a.Equal("object", aliasedSub.Type)
-	a.Equal("string", aliasedSub.Properties["timeField"].Type)
-	a.Equal("date-time", aliasedSub.Properties["timeField"].Format)
+	a.Equal("string", aliasedSub.Properties["TimeField"].Type)
+	a.Equal("date-time", aliasedSub.Properties["TimeField"].Format)
 }
 
 func TestASTInterpreter_Parameter(t *testing.T) {
@@ -90,7 +102,7 @@ func TestASTInterpreter_Parameter(t *testing.T) {
 	a.NoError(openError)
 
 	root := models.Root{}
-	interpreter := &interpret.ASTInterpreter{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
 	a.NoError(interpreter.InterpretFile(file, &root))
 	parameter := root.Components.Parameters["ConstParamName"]
 	a.Equal("constParamName", parameter.Name)
@@ -102,3 +114,1300 @@ func TestASTInterpreter_Parameter(t *testing.T) {
 	a.Equal("query", parameter.In)
 	a.Equal("some text", parameter.Content["text/plain"].Example)
 }
+
+func TestASTInterpreter_Response(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/vars_with_responses.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	response := root.Components.Responses["NotFound"]
+	a.Equal("The resource could not be found", response.Description)
+	a.Equal("not found", response.Content["application/json"].Example)
+
+	response = root.Components.Responses["InternalServerError"]
+	a.Equal("Something went wrong", response.Description)
+}
+
+func TestASTInterpreter_RequestBodyAndHeader(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/vars_with_request_bodies_and_headers.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	requestBody := root.Components.RequestBodies["JSONUpload"]
+	a.Equal("A JSON upload", requestBody.Description)
+	a.True(requestBody.Required)
+	a.Equal(`{"key":"value"}`, requestBody.Content["application/json"].Example)
+
+	header := root.Components.Headers["IdempotencyKey"]
+	a.Equal("A key that makes the request idempotent", header.Description)
+	a.True(header.Required)
+	a.Equal("string", header.Schema.Type)
+	a.Equal("Idempotency key", header.Schema.Title)
+	a.Equal(uint64(8), *header.Schema.MinLength)
+	a.Equal([]interface{}{"00000000"}, header.Schema.Not.Enum)
+}
+
+func TestASTInterpreter_InvalidComponent(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_invalid_test_files/header_with_invalid_yaml.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	err := interpreter.InterpretFile(file, &root)
+	a.Error(err)
+	a.Contains(err.Error(), "failed to decode comment")
+}
+
+func TestASTInterpreter_Security(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/vars_with_security.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+
+	oauth := root.Components.SecuritySchemes["OAuth"]
+	a.Equal("oauth2", oauth.Type)
+	a.Equal("OAuth2 authentication", oauth.Description)
+	a.Equal("https://example.com/oauth/authorize", oauth.Flows.Implicit.AuthorizationURL)
+	a.Equal("Read access", oauth.Flows.Implicit.Scopes["read"])
+
+	apiKey := root.Components.SecuritySchemes["APIKey"]
+	a.Equal("apiKey", apiKey.Type)
+	a.Equal("X-API-Key", apiKey.Name)
+	a.Equal("header", apiKey.In)
+
+	security := *root.Paths["/secured"].Get.Security
+	a.Equal([]string{"read"}, security[0]["OAuth"])
+	a.Empty(security[1]["APIKey"])
+	a.Empty(*root.Paths["/secured"].Post.Security)
+}
+
+func TestASTInterpreter_ServersAndTags(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/main_with_servers_and_tags.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+
+	a.Len(root.Servers, 2)
+	a.Equal("https://staging.example.com/{version}", root.Servers[0].URL)
+	a.Equal("Staging", root.Servers[0].Description)
+	a.Equal("v1", root.Servers[0].Variables["version"].Default)
+	a.Equal([]string{"v1", "v2"}, root.Servers[0].Variables["version"].Enum)
+	a.Equal("https://example.com", root.Servers[1].URL)
+	a.Equal("Production, again", root.Servers[1].Description)
+
+	a.Len(root.Tags, 1)
+	a.Equal("orders", root.Tags[0].Name)
+	a.Equal("Everything about orders", root.Tags[0].Description)
+}
+
+func TestASTInterpreter_Operation(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/funcs_with_operations.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+
+	pathItem := root.Paths["/orders/{id}"]
+	a.Equal("Get an order", pathItem.Get.Summary)
+	a.Equal(true, pathItem.Get.Extensions["x-internal"])
+	a.Equal("id", pathItem.Get.Parameters[0].Name)
+	a.Equal("The order", pathItem.Get.Responses["200"].Description)
+	a.Equal("Delete an order", pathItem.Delete.Summary)
+	a.Equal("The order was deleted", pathItem.Delete.Responses["204"].Description)
+}
+
+func TestASTInterpreter_DuplicateOperation(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_invalid_test_files/funcs_with_duplicate_operations.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	err := interpreter.InterpretFile(file, &root)
+	a.Error(err)
+	a.Contains(err.Error(), "listOrdersAgain")
+	a.Contains(err.Error(), "funcs_with_duplicate_operations.go:20")
+	a.Contains(err.Error(), "operation GET /orders is already defined")
+	a.Equal("The orders", root.Paths["/orders"].Get.Responses["200"].Description)
+}
+
+func TestASTInterpreter_SharedPaths(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/funcs_with_shared_paths.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+
+	health := root.Paths["/health"]
+	a.Equal("Get the health", health.Get.Summary)
+	a.Equal("Report the health", health.Post.Summary)
+
+	status := root.Paths["/status"]
+	a.Equal("The status of the service", status.Description)
+	a.Equal("Get the status", status.Get.Summary)
+	a.Equal("Set the status", status.Put.Summary)
+}
+
+func TestASTInterpreter_DuplicatePathOperation(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_invalid_test_files/funcs_with_duplicate_path_operations.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	err := interpreter.InterpretFile(file, &root)
+	a.Error(err)
+	a.Contains(err.Error(), "operation GET /items is already defined")
+	a.Equal("The items", root.Paths["/items"].Get.Responses["200"].Description)
+}
+
+func TestASTInterpreter_Validation(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_validation.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schema := root.Components.Schemas["validatedModel"]
+
+	a.Equal([]string{"name", "email", "kind", "level", "age", "code", "prefixed", "tags", "mapped", "either"},
+		schema.Required)
+
+	name := schema.Properties["name"]
+	a.Equal(uint64(1), *name.MinLength)
+	a.Equal(uint64(64), *name.MaxLength)
+
+	a.Equal("email", schema.Properties["email"].Format)
+	a.Equal([]interface{}{"a", "b", "c d"}, schema.Properties["kind"].Enum)
+	a.Equal([]interface{}{int64(1), int64(2), int64(3)}, schema.Properties["level"].Enum)
+
+	age := schema.Properties["age"]
+	a.Equal(float64(18), *age.Minimum)
+	a.False(age.ExclusiveMinimum)
+	a.Equal(float64(130), *age.Maximum)
+	a.True(age.ExclusiveMaximum)
+
+	code := schema.Properties["code"]
+	a.Equal(uint64(4), *code.MinLength)
+	a.Equal(uint64(4), *code.MaxLength)
+	a.Equal("^[a-zA-Z0-9]+$", code.Pattern)
+
+	a.Equal(`^a\.b`, schema.Properties["prefixed"].Pattern)
+
+	tags := schema.Properties["tags"]
+	a.Equal(uint64(1), *tags.MinItems)
+	a.Equal(uint64(10), *tags.MaxItems)
+	a.Nil(tags.Items.MinLength)
+
+	a.Nil(schema.Properties["mapped"].MinLength)
+	a.Empty(schema.Properties["either"].Format)
+}
+
+func TestASTInterpreter_EmbeddedStructs(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_embedding.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schemas := root.Components.Schemas
+
+	bike := schemas["bike"]
+	a.Equal("object", bike.Type)
+	a.Empty(bike.AllOf)
+	a.Equal("date-time", bike.Properties["createdAt"].Format)
+	a.Equal("date-time", bike.Properties["updatedAt"].Format)
+	a.Equal("string", bike.Properties["name"].Type)
+	a.Equal("#/components/schemas/owner", bike.Properties["owner"].Ref)
+	a.Equal([]string{"owner", "name", "updatedAt"}, bike.Required)
+
+	bikeListing := schemas["bikeListing"]
+	a.Equal("BikeListing is a page of bikes.", bikeListing.Description)
+	a.Len(bikeListing.Properties, 5)
+	a.Equal("integer", bikeListing.Properties["page"].Type)
+	a.Equal("integer", bikeListing.Properties["pageSize"].Type)
+	a.Equal("array", bikeListing.Properties["items"].Type)
+	a.Contains(bikeListing.Properties, "createdAt")
+	a.Contains(bikeListing.Properties, "updatedAt")
+	a.Equal([]string{"page", "pageSize"}, bikeListing.Required)
+}
+
+func TestASTInterpreter_EmbeddedGenericStructs(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_embedded_generics.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schemas := root.Components.Schemas
+
+	revisedCounter := schemas["revisedCounter"]
+	a.Len(revisedCounter.Properties, 3)
+	a.Equal("integer", revisedCounter.Properties["revision"].Type)
+	a.Equal([]string{"name", "value", "revision"}, revisedCounter.Required)
+
+	labelledMeasurements := schemas["labelledMeasurements"]
+	a.Len(labelledMeasurements.Properties, 2)
+	a.Equal("array", labelledMeasurements.Properties["values"].Type)
+	a.Empty(labelledMeasurements.Required)
+
+	// Without the type checker the fields of a struct that is not declared in the module are unknown
+	scopedCookie := schemas["scopedCookie"]
+	a.Len(scopedCookie.AllOf, 2)
+	a.Equal("#/components/schemas/cookie", scopedCookie.AllOf[0].Ref)
+}
+
+func TestPackagesInterpreter_EmbeddedGenericStructs(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_embedded_generics.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schemas := root.Components.Schemas
+
+	revisedCounter := schemas["revisedCounter"]
+	a.Equal("integer", revisedCounter.Properties["value"].Type)
+	a.Equal("int64", revisedCounter.Properties["value"].Format)
+
+	labelledMeasurements := schemas["labelledMeasurements"]
+	a.Equal("string", labelledMeasurements.Properties["label"].Type)
+	a.Equal("number", labelledMeasurements.Properties["values"].Items.Type)
+
+	scopedCookie := schemas["scopedCookie"]
+	a.Empty(scopedCookie.AllOf)
+	a.Equal("string", scopedCookie.Properties["Name"].Type)
+	a.Equal("date-time", scopedCookie.Properties["Expires"].Format)
+	a.Equal("integer", scopedCookie.Properties["SameSite"].Type)
+	a.Equal("string", scopedCookie.Properties["scope"].Type)
+	a.Contains(scopedCookie.Required, "Name")
+}
+
+func TestASTInterpreter_ExcludedFiles(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_embedding.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{}
+	a.NoError(interpreter.InterpretFile(file, &root))
+
+	// The file is interpreted on request, but the declarations of the files that need the testResource tag are not found
+	bike := root.Components.Schemas["bike"]
+	a.Len(bike.AllOf, 2)
+	a.Equal("#/components/schemas/auditFields", bike.AllOf[0].Ref)
+}
+
+func TestASTInterpreter_EmbeddedConflicts(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_embedded_conflicts.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertEmbeddedConflicts(a, root.Components.Schemas)
+}
+
+func TestPackagesInterpreter_EmbeddedConflicts(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_embedded_conflicts.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertEmbeddedConflicts(a, root.Components.Schemas)
+}
+
+func assertEmbeddedConflicts(a *assert.Assertions, schemas map[string]*models.Schema) {
+	parent := schemas["parent"]
+	a.Len(parent.Properties, 2)
+	a.Equal("string", parent.Properties["name"].Type)
+	a.Equal("integer", parent.Properties["age"].Type)
+	a.Equal([]string{"name"}, parent.Required)
+
+	shipment := schemas["shipment"]
+	var propertyNames []string
+	for propertyName := range shipment.Properties {
+		propertyNames = append(propertyNames, propertyName)
+	}
+	a.ElementsMatch([]string{"weight", "Code", "carrier", "trackingNumber"}, propertyNames)
+	a.Equal([]string{"weight", "Code"}, shipment.Required)
+}
+
+func TestASTInterpreter_EmbeddedStructsAsAllOf(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_embedding.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{
+		Options:   interpret.Options{EmbeddedAsAllOf: true},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schemas := root.Components.Schemas
+
+	bike := schemas["bike"]
+	a.Empty(bike.Type)
+	a.Nil(bike.Properties)
+	a.Len(bike.AllOf, 2)
+	a.Equal("#/components/schemas/auditFields", bike.AllOf[0].Ref)
+	a.Equal("object", bike.AllOf[1].Type)
+	a.Len(bike.AllOf[1].Properties, 3)
+	a.Equal([]string{"owner", "name", "updatedAt"}, bike.AllOf[1].Required)
+
+	bikeListing := schemas["bikeListing"]
+	a.Len(bikeListing.AllOf, 2)
+	a.Equal("#/components/schemas/pagination", bikeListing.AllOf[0].Ref)
+	a.Equal("#/components/schemas/bikes", bikeListing.AllOf[1].Ref)
+}
+
+func TestASTInterpreter_RequiredAndNullable(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_nullable_fields.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schema := root.Components.Schemas["customer"]
+
+	a.Equal([]string{"id", "nickname", "score", "email", "phone"}, schema.Required)
+
+	a.False(schema.Properties["id"].Nullable)
+	a.False(schema.Properties["name"].Nullable)
+	a.True(schema.Properties["referrer"].Nullable)
+	a.Empty(schema.Properties["referrer"].Ref)
+	a.Equal("#/components/schemas/customer", schema.Properties["referrer"].AllOf[0].Ref)
+
+	// encoding/json writes the Null types of database/sql as objects, of which the value may be the zero value
+	nickname := schema.Properties["nickname"]
+	a.False(nickname.Nullable)
+	a.Equal("object", nickname.Type)
+	a.Equal("string", nickname.Properties["String"].Type)
+	a.Equal("boolean", nickname.Properties["Valid"].Type)
+	a.Equal([]string{"String", "Valid"}, nickname.Required)
+
+	score := schema.Properties["score"]
+	a.False(score.Nullable)
+	a.Equal("integer", score.Properties["Int64"].Type)
+	a.Equal("int64", score.Properties["Int64"].Format)
+
+	a.False(schema.Properties["email"].Nullable)
+	a.True(schema.Properties["deletedAt"].Nullable)
+}
+
+func TestASTInterpreter_Enums(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/types_with_enums.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schemas := root.Components.Schemas
+
+	orderStatus := schemas["orderStatus"]
+	a.Equal("string", orderStatus.Type)
+	a.Nil(orderStatus.Properties)
+	a.Equal("OrderStatus is the status of an order.", orderStatus.Description)
+	a.Equal([]interface{}{"shipped", "pending", "paid"}, orderStatus.Enum)
+	a.Equal([]interface{}{"", "StatusPending is an order that awaits payment", "StatusPaid is an order that has been paid"}, orderStatus.Extensions["x-enum-descriptions"])
+
+	priority := schemas["priority"]
+	a.Equal("integer", priority.Type)
+	a.Equal("int64", priority.Format)
+	a.Equal([]interface{}{int64(1), int64(2), int64(8), int64(-1)}, priority.Enum)
+	a.NotContains(priority.Extensions, "x-enum-descriptions")
+}
+
+func TestPackagesInterpreter_Types(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_types.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schema := root.Components.Schemas["measurement"]
+
+	a.Equal("number", schema.Properties["temperature"].Type)
+	a.Equal("double", schema.Properties["temperature"].Format)
+	a.Equal("array", schema.Properties["readings"].Type)
+	a.Equal("number", schema.Properties["readings"].Items.Type)
+	a.Equal("number", schema.Properties["counts"].AdditionalProperties.(*models.Schema).Type)
+	a.Equal("string", schema.Properties["label"].Type)
+	a.Equal("#/components/schemas/orderStatus", schema.Properties["status"].Ref)
+	a.Equal("#/components/schemas/pagination", schema.Properties["page"].Ref)
+	a.Equal("string", schema.Properties["taken"].Type)
+	a.Equal("date-time", schema.Properties["taken"].Format)
+	a.Equal("#/components/schemas/customer", schema.Properties["owners"].AdditionalProperties.(*models.Schema).Ref)
+}
+
+func TestPackagesInterpreter_EmbeddedStructs(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_embedding.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+
+	bikeListing := root.Components.Schemas["bikeListing"]
+	a.Len(bikeListing.Properties, 5)
+	a.Equal("integer", bikeListing.Properties["page"].Type)
+	a.Equal("date-time", bikeListing.Properties["createdAt"].Format)
+	a.Equal([]string{"page", "pageSize"}, bikeListing.Required)
+}
+
+func TestPackagesInterpreter_RecursiveTypes(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_recursive_types.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schemas := root.Components.Schemas
+
+	forest := schemas["forest"]
+	a.Equal("object", forest.Properties["trees"].Type)
+	a.Equal("#/components/schemas/tree", forest.Properties["trees"].AdditionalProperties.(*models.Schema).Ref)
+	a.Equal("array", forest.Properties["trails"].Type)
+	a.Equal("#/components/schemas/trail", forest.Properties["trails"].Items.Ref)
+
+	a.Equal("Tree is a tree of names.", schemas["tree"].Description)
+	a.Equal("object", schemas["tree"].Type)
+	a.Equal("#/components/schemas/tree", schemas["tree"].AdditionalProperties.(*models.Schema).Ref)
+	a.Equal("array", schemas["trail"].Type)
+	a.Equal("#/components/schemas/trail", schemas["trail"].Items.Ref)
+}
+
+func TestPackagesInterpreter_Enums(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/types_with_enums.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+
+	a.Equal([]interface{}{"shipped", "pending", "paid"}, root.Components.Schemas["orderStatus"].Enum)
+	a.Equal([]interface{}{int64(1), int64(2), int64(8), int64(-1)}, root.Components.Schemas["priority"].Enum)
+}
+
+func TestPackagesInterpreter_ExcludedFile(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_types.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	a.Nil(root.Components)
+}
+
+func TestASTInterpreter_ReferencedTypes(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_references.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schemas := root.Components.Schemas
+
+	a.Len(schemas, 1)
+	a.Equal("#/components/schemas/address", schemas["shop"].Properties["address"].Ref)
+	a.Equal("#/components/schemas/hours", schemas["shop"].Properties["opening"].Ref)
+}
+
+func TestASTInterpreter_IncludeReferencedTypes(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_references.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{
+		Options:   interpret.Options{IncludeReferencedTypes: true},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertReferencedTypes(a, root.Components.Schemas)
+}
+
+func TestPackagesInterpreter_IncludeReferencedTypes(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_references.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{
+		Options:   interpret.Options{IncludeReferencedTypes: true},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertReferencedTypes(a, root.Components.Schemas)
+}
+
+func assertReferencedTypes(a *assert.Assertions, schemas map[string]*models.Schema) {
+	a.Len(schemas, 4)
+
+	address := schemas["address"]
+	a.Equal("Address is where a shop is located.", address.Description)
+	a.Equal("string", address.Properties["street"].Type)
+
+	category := schemas["category"]
+	a.Equal("#/components/schemas/category", category.Properties["parent"].AllOf[0].Ref)
+	a.Equal("#/components/schemas/category", category.Properties["children"].Items.Ref)
+
+	hours := schemas["hours"]
+	a.Equal("string", hours.Properties["opens"].Type)
+}
+
+func TestASTInterpreter_GoTypes(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/funcs_with_go_types.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schemas := root.Components.Schemas
+
+	shops := root.Paths["/shops"].Get.Responses["200"].Content["application/json"].Schema
+	a.Equal("array", shops.Type)
+	a.Equal("#/components/schemas/shop", shops.Items.Ref)
+	a.Empty(shops.Items.GoType)
+	a.Equal("object", schemas["shop"].Type)
+
+	hours := root.Paths["/shops/{id}/hours"].Put.RequestBody.Content["application/json"].Schema
+	a.Equal("#/components/schemas/hours", hours.Ref)
+	a.Equal("string", schemas["hours"].Properties["opens"].Type)
+
+	addresses := root.Paths["/shops/{id}/hours"].Put.Responses["200"].Content["application/json"].Schema
+	a.Equal("object", addresses.Type)
+	a.Equal("#/components/schemas/address", addresses.AdditionalProperties.(*models.Schema).Ref)
+	a.Contains(schemas, "address")
+
+	serverTime := root.Components.Responses["ServerTime"].Content["application/json"].Schema
+	a.Equal("string", serverTime.Type)
+	a.Equal("date-time", serverTime.Format)
+
+	attachment := root.Components.Responses["Attachment"].Content
+	a.Equal("string", attachment["application/octet-stream"].Schema.Type)
+	a.Equal("byte", attachment["application/octet-stream"].Schema.Format)
+	a.Equal("object", attachment["application/json"].Schema.Type)
+	a.Equal(&models.Schema{}, attachment["application/json"].Schema.AdditionalProperties)
+	a.Equal(&models.Schema{}, attachment["text/plain"].Schema)
+}
+
+func TestPackagesInterpreter_GoTypes(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/funcs_with_go_types.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schemas := root.Components.Schemas
+
+	hours := root.Paths["/shops/{id}/hours"].Put.RequestBody.Content["application/json"].Schema
+	a.Equal("#/components/schemas/hours", hours.Ref)
+	a.Equal("string", schemas["hours"].Properties["opens"].Type)
+
+	serverTime := root.Components.Responses["ServerTime"].Content["application/json"].Schema
+	a.Equal("date-time", serverTime.Format)
+
+	attachment := root.Components.Responses["Attachment"].Content
+	a.Equal("byte", attachment["application/octet-stream"].Schema.Format)
+	a.Equal(&models.Schema{}, attachment["application/json"].Schema.AdditionalProperties)
+	a.Equal(&models.Schema{}, attachment["text/plain"].Schema)
+}
+
+func TestASTInterpreter_UnknownGoType(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_invalid_test_files/funcs_with_unknown_go_types.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	err := interpreter.InterpretFile(file, &root)
+	a.Error(err)
+	a.Contains(err.Error(), "goType MissingModel")
+	a.Contains(err.Error(), "funcs_with_unknown_go_types.go:13")
+	a.Contains(err.Error(), "type MissingModel is not declared in the module")
+}
+
+func TestASTInterpreter_ExplicitSchemaNames(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_names.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schemas := root.Components.Schemas
+
+	a.Len(schemas, 2)
+	a.Equal("#/components/schemas/Day", schemas["Schedule"].Properties["days"].Items.Ref)
+	a.Equal("string", schemas["Day"].Properties["weekday"].Type)
+}
+
+func TestASTInterpreter_SchemaNaming(t *testing.T) {
+	for naming, expectedNames := range map[interpret.SchemaNaming][]string{
+		interpret.AsIsSchemaNaming:             {"Pagination", "Hours", "URL"},
+		interpret.PackageQualifiedSchemaNaming: {"shared.Pagination", "shared.Hours", "url.URL"},
+		interpret.PackagePrefixedSchemaNaming:  {"SharedPagination", "SharedHours", "UrlURL"},
+	} {
+		a := assert.New(t)
+		root := models.Root{}
+		interpreter := &interpret.ASTInterpreter{
+			Options:   interpret.Options{SchemaNaming: naming, IncludeReferencedTypes: true},
+			BuildTags: []string{"testResource"},
+		}
+		for _, fileName := range []string{"./_test_files/shared/pagination.go", "./_test_files/structs_with_references.go"} {
+			file, openError := os.Open(fileName)
+			a.NoError(openError)
+			a.NoError(interpreter.InterpretFile(file, &root))
+		}
+		schemas := root.Components.Schemas
+
+		a.Contains(schemas, expectedNames[0], naming)
+		a.Contains(schemas, expectedNames[1], naming)
+		for _, schema := range schemas {
+			if opening, ok := schema.Properties["opening"]; ok {
+				a.Equal("#/components/schemas/"+expectedNames[1], opening.Ref, naming)
+			}
+			if website, ok := schema.Properties["website"]; ok {
+				a.Equal("#/components/schemas/"+expectedNames[2], website.Ref, naming)
+			}
+		}
+	}
+}
+
+func TestASTInterpreter_SchemaNameCollision(t *testing.T) {
+	a := assert.New(t)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	orderFile, openError := os.Open("./_invalid_test_files/order/response.go")
+	a.NoError(openError)
+	a.NoError(interpreter.InterpretFile(orderFile, &root))
+
+	userFile, openError := os.Open("./_invalid_test_files/user/response.go")
+	a.NoError(openError)
+	err := interpreter.InterpretFile(userFile, &root)
+	a.Error(err)
+	a.Contains(err.Error(), "user/response.go:6")
+	a.Contains(err.Error(), "order/response.go:6")
+	a.Contains(err.Error(), "both named response")
+
+	root = models.Root{}
+	interpreter = &interpret.ASTInterpreter{
+		Options:   interpret.Options{SchemaNaming: interpret.PackageQualifiedSchemaNaming},
+		BuildTags: []string{"testResource"},
+	}
+	orderFile, openError = os.Open("./_invalid_test_files/order/response.go")
+	a.NoError(openError)
+	a.NoError(interpreter.InterpretFile(orderFile, &root))
+	userFile, openError = os.Open("./_invalid_test_files/user/response.go")
+	a.NoError(openError)
+	a.NoError(interpreter.InterpretFile(userFile, &root))
+	a.Contains(root.Components.Schemas, "order.Response")
+	a.Contains(root.Components.Schemas, "user.Response")
+}
+
+func TestASTInterpreter_TypeMappings(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_mapped_types.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	typeMappings := moneyTypeMappings()
+	interpreter := &interpret.ASTInterpreter{
+		Options:   interpret.Options{TypeMappings: typeMappings},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertMappedTypes(a, root.Components.Schemas["invoice"])
+	a.Equal(moneyTypeMappings(), typeMappings)
+}
+
+func TestPackagesInterpreter_TypeMappings(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_mapped_types.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	typeMappings := moneyTypeMappings()
+	interpreter := &interpret.PackagesInterpreter{
+		Options:   interpret.Options{TypeMappings: typeMappings},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertMappedTypes(a, root.Components.Schemas["invoice"])
+	a.Equal(moneyTypeMappings(), typeMappings)
+}
+
+func TestASTInterpreter_TypeMappingsOverrideDefaults(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_mapped_types.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	typeMappings := map[string]*models.Schema{"time.Duration": {Type: "string", Example: "1h30m"}}
+	interpreter := &interpret.ASTInterpreter{
+		Options:   interpret.Options{TypeMappings: typeMappings},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	invoice := root.Components.Schemas["invoice"]
+
+	a.Equal("string", invoice.Properties["due"].Type)
+	a.Equal("1h30m", invoice.Properties["due"].Example)
+	a.Equal("#/components/schemas/money", invoice.Properties["total"].Ref)
+}
+
+func moneyTypeMappings() map[string]*models.Schema {
+	return map[string]*models.Schema{
+		"github.com/VanMoof/gopenapi/interpret/_test_files/shared.Money": {Type: "string", Pattern: "^[A-Z]{3} [0-9]+\\.[0-9]{2}$"},
+	}
+}
+
+func assertMappedTypes(a *assert.Assertions, invoice *models.Schema) {
+	total := invoice.Properties["total"]
+	a.Equal("string", total.Type)
+	a.Equal("^[A-Z]{3} [0-9]+\\.[0-9]{2}$", total.Pattern)
+	a.Equal("The price of the invoice", total.Description)
+	a.False(total.Nullable)
+	a.True(invoice.Properties["refunded"].Nullable)
+	a.Equal("string", invoice.Properties["items"].Items.Type)
+
+	a.Equal("integer", invoice.Properties["due"].Type)
+	a.Equal("int64", invoice.Properties["due"].Format)
+	a.Empty(invoice.Properties["payload"].Type)
+	a.Equal("string", invoice.Properties["issuer"].Type)
+	a.Equal([]string{"total", "items", "due", "payload", "issuer"}, invoice.Required)
+}
+
+func TestPackagesInterpreter_Marshalers(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_marshalers.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	var warnings bytes.Buffer
+	interpreter := &interpret.PackagesInterpreter{
+		Options:   interpret.Options{IncludeReferencedTypes: true, Warnings: &warnings},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	schemas := root.Components.Schemas
+
+	store := schemas["store"]
+	a.Equal("string", store.Properties["color"].Type)
+	a.Empty(store.Properties["color"].Properties)
+	a.Equal("#/components/schemas/coordinates", store.Properties["location"].Ref)
+	a.Empty(store.Properties["secret"].Type)
+	a.Empty(store.Properties["secret"].Ref)
+	a.True(store.Properties["secret"].Nullable)
+	a.NotContains(schemas, "color")
+	a.NotContains(schemas, "secret")
+
+	coordinates := schemas["coordinates"]
+	a.Equal("Coordinates are the longitude and the latitude of a location.", coordinates.Description)
+	a.Equal("array", coordinates.Type)
+	a.Equal("number", coordinates.Items.Type)
+	a.Equal(uint64(2), *coordinates.MinItems)
+	a.Empty(coordinates.Properties)
+
+	a.Equal("warning: github.com/VanMoof/gopenapi/interpret/_test_files.Secret implements json.Marshaler, so its "+
+		"schema is free-form. Annotate it with gopenapi:objectSchema followed by its schema, or map it with a type "+
+		"mapping\n", warnings.String())
+}
+
+func TestASTInterpreter_TypeShapes(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_shapes.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	var warnings bytes.Buffer
+	interpreter := &interpret.ASTInterpreter{
+		Options:   interpret.Options{Warnings: &warnings},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertTypeShapes(a, root.Components.Schemas, warnings.String())
+}
+
+func TestPackagesInterpreter_TypeShapes(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_shapes.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	var warnings bytes.Buffer
+	interpreter := &interpret.PackagesInterpreter{
+		Options:   interpret.Options{Warnings: &warnings},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertTypeShapes(a, root.Components.Schemas, warnings.String())
+}
+
+func assertTypeShapes(a *assert.Assertions, schemas map[string]*models.Schema, warnings string) {
+	shapes := schemas["shapes"]
+	a.Equal("string", shapes.Properties["names"].Items.Type)
+	a.Equal("integer", shapes.Properties["counts"].Items.Type)
+	a.Equal("string", shapes.Properties["labels"].AdditionalProperties.(*models.Schema).Type)
+	a.Equal("array", shapes.Properties["grid"].Items.Type)
+	a.Equal("#/components/schemas/subModel", shapes.Properties["grid"].Items.Items.Ref)
+	a.Equal("array", shapes.Properties["digest"].Type)
+	a.Equal("integer", shapes.Properties["digest"].Items.Type)
+	a.Equal("string", shapes.Properties["blob"].Type)
+	a.Equal("byte", shapes.Properties["blob"].Format)
+	a.Equal(&models.Schema{}, shapes.Properties["anything"])
+	a.Equal(&models.Schema{}, shapes.Properties["payload"])
+	a.NotContains(shapes.Properties, "updates")
+	a.NotContains(shapes.Properties, "callback")
+	a.Equal("integer", shapes.Properties["Width"].Type)
+	a.Equal("integer", shapes.Properties["Height"].Type)
+
+	matrix := schemas["matrix"]
+	a.Equal("Matrix is a grid of numbers", matrix.Description)
+	a.Equal("number", matrix.Items.Items.Type)
+	a.NotContains(schemas, "listener")
+
+	a.Contains(warnings, "skipping field updates at ")
+	a.Contains(warnings, "structs_with_shapes.go:15:2: encoding/json does not support the type chan string")
+	a.Contains(warnings, "skipping field callback at ")
+	a.Contains(warnings, "skipping type Listener at ")
+}
+
+func TestASTInterpreter_AnonymousStructs(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_envelopes.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertAnonymousStructs(a, root.Components.Schemas)
+}
+
+func TestPackagesInterpreter_AnonymousStructs(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_envelopes.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertAnonymousStructs(a, root.Components.Schemas)
+}
+
+func assertAnonymousStructs(a *assert.Assertions, schemas map[string]*models.Schema) {
+	a.Len(schemas, 3)
+	envelope := schemas["itemsEnvelope"]
+	a.Equal("#/components/schemas/subModel", envelope.Properties["data"].Items.Ref)
+	a.Equal([]string{"data", "meta", "filter"}, envelope.Required)
+
+	meta := envelope.Properties["meta"]
+	a.Equal("The metadata of the page", meta.Description)
+	a.Equal("object", meta.Type)
+	a.Equal("integer", meta.Properties["total"].Type)
+	a.Equal([]string{"total"}, meta.Required)
+	a.True(meta.Properties["links"].Nullable)
+	a.Equal("string", meta.Properties["links"].Properties["next"].Type)
+	a.Empty(meta.Properties["links"].Required)
+
+	errors := envelope.Properties["errors"]
+	a.Equal("object", errors.Items.Type)
+	a.Equal("string", errors.Items.Properties["message"].Type)
+
+	cursor := envelope.Properties["cursor"]
+	a.Empty(cursor.Ref)
+	a.Equal("#/components/schemas/cursor", cursor.AllOf[0].Ref)
+	a.True(cursor.Nullable)
+	a.Equal("string", schemas["cursor"].Properties["after"].Type)
+
+	a.Equal("#/components/schemas/itemsFilter", envelope.Properties["filter"].Ref)
+	labels := schemas["itemsFilter"].Properties["labels"]
+	a.Equal("object", labels.AdditionalProperties.(*models.Schema).Type)
+	a.Empty(labels.AdditionalProperties.(*models.Schema).Properties)
+}
+
+func TestASTInterpreter_HoistedStructFieldOfNamedType(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_invalid_test_files/structs_with_hoisted_named_type.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	err := interpreter.InterpretFile(file, &root)
+	a.Error(err)
+	a.Contains(err.Error(), "gopenapi:objectSchema of field page at ")
+	a.Contains(err.Error(), "structs_with_hoisted_named_type.go:8:2 should annotate a field of an anonymous struct")
+}
+
+func TestASTInterpreter_SchemaOverrides(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_overrides.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertSchemaOverrides(a, root.Components.Schemas)
+}
+
+func TestPackagesInterpreter_SchemaOverrides(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_overrides.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertSchemaOverrides(a, root.Components.Schemas)
+}
+
+func assertSchemaOverrides(a *assert.Assertions, schemas map[string]*models.Schema) {
+	a.NotContains(schemas, "vehicle")
+	vehicle := schemas["vehicleResource"]
+	a.Equal("Vehicle is a bike or a scooter.", vehicle.Description)
+	a.Equal("Vehicle", vehicle.Title)
+	a.Equal("object", vehicle.Type)
+	a.Equal(map[string]interface{}{"kind": "bike", "frame": 42}, vehicle.Example)
+	a.Equal("kind", vehicle.Discriminator.PropertyName)
+	a.Equal([]string{"kind", "opening", "frame"}, vehicle.Required)
+	a.Equal(true, vehicle.Extensions["x-internal"])
+	a.Equal("string", vehicle.Properties["kind"].Type)
+	frame := vehicle.Properties["frame"]
+	a.Equal("integer", frame.Type)
+	a.Equal("The number of the frame", frame.Description)
+	a.Equal(float64(1), *frame.Minimum)
+	a.Equal("#/components/schemas/hours", vehicle.Properties["opening"].Ref)
+	a.Contains(schemas, "hours")
+
+	fleet := schemas["fleet"]
+	a.Equal("#/components/schemas/vehicleResource", fleet.Properties["vehicles"].Items.Ref)
+	a.Equal("#/components/schemas/fleetOwner", fleet.Properties["owner"].Ref)
+	owner := schemas["fleetOwner"]
+	a.Equal("The owner of the fleet", owner.Description)
+	a.Equal([]string{"name"}, owner.Required)
+}
+
+func TestASTInterpreter_FieldOptions(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_field_options.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	var warnings bytes.Buffer
+	interpreter := &interpret.ASTInterpreter{
+		Options:   interpret.Options{Warnings: &warnings},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertFieldOptions(a, root.Components.Schemas["listing"])
+	a.Contains(warnings.String(), "ignoring the unknown option colour of the openapi tag at")
+	a.Contains(warnings.String(), "structs_with_field_options.go:13")
+}
+
+func TestPackagesInterpreter_FieldOptions(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_field_options.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertFieldOptions(a, root.Components.Schemas["listing"])
+}
+
+func assertFieldOptions(a *assert.Assertions, listing *models.Schema) {
+	id := listing.Properties["id"]
+	a.True(id.ReadOnly)
+	a.Equal("string", id.Type)
+	a.Equal("uuid", id.Format)
+	a.True(listing.Properties["password"].WriteOnly)
+
+	limit := listing.Properties["limit"]
+	a.Equal(int64(42), limit.Example)
+	a.Equal(int64(10), limit.Default)
+	a.Equal(0.5, listing.Properties["ratio"].Default)
+	a.Equal("asc", listing.Properties["order"].Default)
+	a.Equal("The order of the items", listing.Properties["order"].Description)
+	a.True(listing.Properties["legacy"].Deprecated)
+	a.Equal(false, listing.Properties["legacy"].Default)
+	a.Equal("The sort, ascending", listing.Properties["sort"].Description)
+	a.Equal("name,price", listing.Properties["sort"].Example)
+
+	labels := listing.Properties["labels"]
+	a.Equal("The labels of the listing", labels.Description)
+	a.Equal([]interface{}{"new", "sale"}, labels.Example)
+	a.Equal("string", labels.Items.Type)
+	a.Equal(uint64(1), *labels.Items.MinLength)
+
+	price := listing.Properties["price"]
+	a.Equal("The price of the listing", price.Description)
+	a.Equal(12.5, price.Example)
+	a.True(price.ReadOnly)
+	a.True(price.Nullable)
+	a.Equal([]string{"id", "password", "labels"}, listing.Required)
+}
+
+func TestASTInterpreter_JSONTags(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_json_tags.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertJSONTags(a, root.Components.Schemas["account"])
+	a.Equal("#/components/schemas/code", root.Components.Schemas["account"].Properties["Code"].Ref)
+}
+
+func TestPackagesInterpreter_JSONTags(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_json_tags.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertJSONTags(a, root.Components.Schemas["account"])
+	a.Equal("string", root.Components.Schemas["account"].Properties["Code"].Type)
+}
+
+func assertJSONTags(a *assert.Assertions, account *models.Schema) {
+	var propertyNames []string
+	for propertyName := range account.Properties {
+		propertyNames = append(propertyNames, propertyName)
+	}
+	a.ElementsMatch([]string{"Code", "ID", "balance", "active", "nickname", "quantity", "tier", "Email", "Phone", "-",
+		"Weird", "First", "Last"}, propertyNames)
+	a.Equal([]string{"Code", "ID", "balance", "nickname", "quantity", "tier", "Phone", "-", "Weird", "First", "Last"},
+		account.Required)
+
+	a.Equal("string", account.Properties["ID"].Type)
+	a.Equal("int64", account.Properties["ID"].Format)
+	a.Equal("string", account.Properties["balance"].Type)
+	a.Equal("string", account.Properties["active"].Type)
+	a.True(account.Properties["active"].Nullable)
+	a.Equal("string", account.Properties["nickname"].Type)
+
+	quantity := account.Properties["quantity"]
+	a.Equal("string", quantity.Type)
+	a.Nil(quantity.Minimum)
+	a.Equal([]interface{}{"1", "2", "3"}, quantity.Enum)
+	a.Equal("#/components/schemas/tier", account.Properties["tier"].Ref)
+	a.Empty(account.Properties["tier"].Type)
+}
+
+func TestASTInterpreter_XMLTags(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_xml_and_yaml.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{BuildTags: []string{"testResource"}}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	book := root.Components.Schemas["book"]
+
+	a.Equal(&models.XML{Name: "book", Namespace: "http://example.com/books"}, book.XML)
+	a.NotContains(book.Properties, "XMLName")
+	a.Equal(&models.XML{Name: "created"}, book.Properties["createdAt"].XML)
+	a.Equal(&models.XML{Name: "id", Attribute: true}, book.Properties["id"].XML)
+	a.Equal(&models.XML{Name: "authors", Wrapped: true}, book.Properties["authors"].XML)
+	a.Equal(&models.XML{Name: "author"}, book.Properties["authors"].Items.XML)
+	a.Nil(book.Properties["tags"].XML)
+	a.Equal(&models.XML{Name: "tag"}, book.Properties["tags"].Items.XML)
+	a.Equal(&models.XML{Name: "publisher", Namespace: "http://example.com/publishers"}, book.Properties["publisher"].XML)
+	a.Nil(book.Properties["internal"].XML)
+}
+
+func TestASTInterpreter_YAMLNameTag(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_xml_and_yaml.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.ASTInterpreter{
+		Options:   interpret.Options{NameTag: interpret.YAMLNameTag},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertYAMLNameTag(a, root.Components.Schemas["book"])
+}
+
+func TestPackagesInterpreter_YAMLNameTag(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_xml_and_yaml.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	interpreter := &interpret.PackagesInterpreter{
+		Options:   interpret.Options{NameTag: interpret.YAMLNameTag},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertYAMLNameTag(a, root.Components.Schemas["book"])
+}
+
+func assertYAMLNameTag(a *assert.Assertions, book *models.Schema) {
+	var propertyNames []string
+	for propertyName := range book.Properties {
+		propertyNames = append(propertyNames, propertyName)
+	}
+	a.ElementsMatch([]string{"created_at", "id", "book_title", "authors", "tags", "publisher"}, propertyNames)
+	a.Equal([]string{"id", "book_title", "tags", "publisher", "created_at"}, book.Required)
+	a.Equal(&models.XML{Name: "title"}, book.Properties["book_title"].XML)
+}
+
+func TestASTInterpreter_PolymorphicSchemas(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_events.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	var warnings bytes.Buffer
+	interpreter := &interpret.ASTInterpreter{
+		Options:   interpret.Options{Warnings: &warnings},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertPolymorphicSchemas(a, root.Components.Schemas, warnings.String())
+}
+
+func TestPackagesInterpreter_PolymorphicSchemas(t *testing.T) {
+	a := assert.New(t)
+
+	file, openError := os.Open("./_test_files/structs_with_events.go")
+	a.NoError(openError)
+
+	root := models.Root{}
+	var warnings bytes.Buffer
+	interpreter := &interpret.PackagesInterpreter{
+		Options:   interpret.Options{Warnings: &warnings},
+		BuildTags: []string{"testResource"},
+	}
+	a.NoError(interpreter.InterpretFile(file, &root))
+	assertPolymorphicSchemas(a, root.Components.Schemas, warnings.String())
+}
+
+func assertPolymorphicSchemas(a *assert.Assertions, schemas map[string]*models.Schema, warnings string) {
+	event := schemas["event"]
+	a.Equal("Event is something that happened to a resource", event.Description)
+	a.Empty(event.Type)
+	a.Empty(event.Properties)
+	a.Equal([]*models.Schema{
+		{Ref: "#/components/schemas/forwarded"},
+		{Ref: "#/components/schemas/resourceArchived"},
+		{Ref: "#/components/schemas/resourceCreated"},
+		{Ref: "#/components/schemas/resourceDeleted"},
+		{Ref: "#/components/schemas/resourcePurged"},
+		{Ref: "#/components/schemas/resourceRenamed"},
+	}, event.OneOf)
+	a.Equal(&models.Discriminator{
+		PropertyName: "kind",
+		Mapping: map[string]string{
+			"archived": "#/components/schemas/resourceArchived",
+			"created":  "#/components/schemas/resourceCreated",
+			"deleted":  "#/components/schemas/resourceDeleted",
+			"purged":   "#/components/schemas/resourcePurged",
+			"renamed":  "#/components/schemas/resourceRenamed",
+		},
+	}, event.Discriminator)
+	a.Contains(warnings, "the Type method of Forwarded at ")
+	a.Contains(warnings, "shared/events.go:8:6 does not return a constant, so it is left out of the mapping of the discriminator of Event")
+	a.Contains(warnings, "the property kind of the discriminator of Event is not required by ResourceArchived at ")
+	a.Contains(warnings, "the property kind of the discriminator of Event is missing from ResourcePurged at ")
+	a.NotContains(warnings, "ResourceCreated")
+
+	a.Equal("#/components/schemas/event", schemas["eventLog"].Properties["events"].Items.Ref)
+
+	notification := schemas["notification"]
+	a.Equal([]*models.Schema{
+		{Ref: "#/components/schemas/emailNotification"},
+		{Ref: "#/components/schemas/pushNotification"},
+	}, notification.OneOf)
+	a.Equal(&models.Discriminator{PropertyName: "channel"}, notification.Discriminator)
+}
@@ -0,0 +1,137 @@
+package interpret
+
+import (
+	"encoding/json"
+	"github.com/VanMoof/gopenapi/models"
+	"go/ast"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultTypeMappings map common types of the standard library and of the ecosystem, by their import path and name,
+// to the schema of their JSON representation. They are consulted after the type mappings of the options
+var DefaultTypeMappings = map[string]*models.Schema{
+	"time.Duration":                             {Type: "integer", Format: "int64"},
+	"encoding/json.RawMessage":                  {},
+	"encoding/json/jsontext.Value":              {},
+	"net.IP":                                    {Type: "string"},
+	"net/netip.Addr":                            {Type: "string"},
+	"net/netip.AddrPort":                        {Type: "string"},
+	"net/netip.Prefix":                          {Type: "string"},
+	"math/big.Int":                              {Type: "integer"},
+	"github.com/google/uuid.UUID":               {Type: "string", Format: "uuid"},
+	"github.com/gofrs/uuid.UUID":                {Type: "string", Format: "uuid"},
+	"github.com/satori/go.uuid.UUID":            {Type: "string", Format: "uuid"},
+	"github.com/shopspring/decimal.Decimal":     {Type: "string", Pattern: `^-?[0-9]+(\.[0-9]+)?$`, Example: "12.34"},
+	"github.com/shopspring/decimal.NullDecimal": {Type: "string", Pattern: `^-?[0-9]+(\.[0-9]+)?$`, Example: "12.34", Nullable: true},
+	"encoding/xml.Name": {Type: "object", Properties: map[string]*models.Schema{
+		"Space": {Type: "string"},
+		"Local": {Type: "string"},
+	}},
+}
+
+// mappedSchema returns the schema to which the type mappings map the qualified name of a type, or nil when the type
+// is not mapped
+func (i *interpretation) mappedSchema(qualifiedName string) *models.Schema {
+	mapped, ok := i.options.TypeMappings[qualifiedName]
+	if !ok {
+		mapped, ok = DefaultTypeMappings[qualifiedName]
+	}
+	if !ok {
+		return nil
+	}
+	return mapped
+}
+
+// setMappedSchema sets the schema to a copy of the schema to which the type is mapped, and reports whether the type is
+// mapped at all. The description of the schema is kept, unless the mapping has one of its own
+func (i *interpretation) setMappedSchema(schema *models.Schema, qualifiedName string) (bool, error) {
+	mapped := i.mappedSchema(qualifiedName)
+	if mapped == nil {
+		return false, nil
+	}
+	// The mapped schema is copied, so that the constraints added to one property do not end up in all of them
+	mappedJSON, err := json.Marshal(mapped)
+	if err != nil {
+		return false, err
+	}
+	description, deprecated := schema.Description, schema.Deprecated
+	*schema = models.Schema{}
+	err = json.Unmarshal(mappedJSON, schema)
+	if err != nil {
+		return false, err
+	}
+	if schema.Description == "" {
+		schema.Description = description
+	}
+	schema.Deprecated = schema.Deprecated || deprecated
+	return true, nil
+}
+
+// qualifiedTypeName returns the name of a named type, qualified by the import path of the package that declares it.
+// The import path of another package is derived from the imports of the file, and predeclared types are not qualified
+func (i *interpretation) qualifiedTypeName(typeExpr ast.Expr) string {
+	switch typeExpr.(type) {
+	case *ast.Ident:
+		name := typeExpr.(*ast.Ident).Name
+		if _, isPredeclared := types.Universe.Lookup(name).(*types.TypeName); isPredeclared || i.pkg.path == "" {
+			return name
+		}
+		return i.pkg.path + "." + name
+	case *ast.SelectorExpr:
+		selectorExpr := typeExpr.(*ast.SelectorExpr)
+		packageName, ok := selectorExpr.X.(*ast.Ident)
+		if !ok {
+			return types.ExprString(typeExpr)
+		}
+		return importPathOfName(i.file, packageName.Name) + "." + selectorExpr.Sel.Name
+	}
+	return types.ExprString(typeExpr)
+}
+
+// qualifiedName returns the name of a type resolved by the type checker, qualified by the import path of its package
+func qualifiedName(typeName *types.TypeName) string {
+	if typeName.Pkg() == nil {
+		return typeName.Name()
+	}
+	return typeName.Pkg().Path() + "." + typeName.Name()
+}
+
+// importPathOfName returns the import path of the package that the file imports under the name. Without an explicit
+// name the name of a package is guessed from its import path, and the name itself is returned when nothing matches
+func importPathOfName(file *ast.File, name string) string {
+	for _, importSpec := range file.Imports {
+		importPath, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if importSpec.Name != nil {
+			if importSpec.Name.Name == name {
+				return importPath
+			}
+			continue
+		}
+		if guessPackageName(importPath) == name {
+			return importPath
+		}
+	}
+	return name
+}
+
+var majorVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+var versionSuffixPattern = regexp.MustCompile(`\.v[0-9]+$`)
+
+// guessPackageName guesses the name of a package from its import path, following the conventions of major version
+// suffixes like github.com/org/name/v2 and gopkg.in/name.v2, and of go- and go. prefixes
+func guessPackageName(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	name := elements[len(elements)-1]
+	if majorVersionPattern.MatchString(name) && len(elements) > 1 {
+		name = elements[len(elements)-2]
+	}
+	name = versionSuffixPattern.ReplaceAllString(name, "")
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "go-"), "go.")
+	return strings.ReplaceAll(name, "-", "")
+}
@@ -0,0 +1,74 @@
+package interpret
+
+import (
+	"github.com/VanMoof/gopenapi/models"
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// jsonMarshaler and textMarshaler are the interfaces json.Marshaler and encoding.TextMarshaler, of which encoding/json
+// calls the methods instead of writing the fields of a type
+var jsonMarshaler = marshalerInterface("MarshalJSON")
+var textMarshaler = marshalerInterface("MarshalText")
+
+func marshalerInterface(methodName string) *types.Interface {
+	results := types.NewTuple(
+		types.NewVar(token.NoPos, nil, "", types.NewSlice(types.Typ[types.Byte])),
+		types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type()),
+	)
+	method := types.NewFunc(token.NoPos, nil, methodName, types.NewSignatureType(nil, nil, nil, nil, results, false))
+	return types.NewInterfaceType([]*types.Func{method}, nil).Complete()
+}
+
+// implements reports whether the type or a pointer to it implements the interface, as encoding/json also calls the
+// methods with a pointer receiver of the values that it can address
+func implements(t types.Type, iface *types.Interface) bool {
+	if types.Implements(t, iface) {
+		return true
+	}
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+		return false
+	}
+	return types.Implements(types.NewPointer(t), iface)
+}
+
+// setSchemaOfMarshaler sets the schema of a type that marshals itself and reports whether it does. An
+// encoding.TextMarshaler is written as a string, but nothing tells what a json.Marshaler writes, so its schema is left
+// free-form with a warning
+func (i *interpretation) setSchemaOfMarshaler(schema *models.Schema, named *types.Named) bool {
+	if implements(named, jsonMarshaler) {
+		i.warn("%s implements json.Marshaler, so its schema is free-form. Annotate it with gopenapi:objectSchema "+
+			"followed by its schema, or map it with a type mapping", qualifiedName(named.Obj()))
+		return true
+	}
+	if implements(named, textMarshaler) {
+		schema.Type = "string"
+		return true
+	}
+	return false
+}
+
+// schemaOfMarshalerDeclaration empties the schema of a declared type that marshals itself and reports whether it does.
+// A json.Marshaler gets the schema that follows its gopenapi:objectSchema annotation, and a warning when there is none
+func (i *interpretation) schemaOfMarshalerDeclaration(schema *models.Schema, typeSpec *ast.TypeSpec, doc *ast.CommentGroup) bool {
+	typeName, ok := i.pkg.typesInfo.Defs[typeSpec.Name].(*types.TypeName)
+	if !ok {
+		return false
+	}
+	t := typeName.Type()
+	if implements(t, jsonMarshaler) {
+		*schema = models.Schema{Description: schema.Description}
+		if a := objectSchemaAnnotation(doc); a == nil || a.content == "" {
+			i.warn("%s at %s implements json.Marshaler, so its schema is free-form. Follow its gopenapi:objectSchema "+
+				"annotation with its schema", typeSpec.Name.Name, i.pkg.fileSet.Position(typeSpec.Pos()))
+		}
+		return true
+	}
+	if implements(t, textMarshaler) {
+		*schema = models.Schema{Type: "string", Description: schema.Description}
+		return true
+	}
+	return false
+}
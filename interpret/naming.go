@@ -0,0 +1,98 @@
+package interpret
+
+import (
+	"fmt"
+	"github.com/VanMoof/gopenapi/models"
+	"go/ast"
+	"go/token"
+	"unicode"
+)
+
+// SchemaNaming is the strategy that derives the names of schemas from the names of types and their packages
+type SchemaNaming string
+
+const (
+	// LowerCamelSchemaNaming names the schema of order.Response "response"
+	LowerCamelSchemaNaming SchemaNaming = "lowerCamel"
+	// AsIsSchemaNaming names the schema of order.Response "Response"
+	AsIsSchemaNaming SchemaNaming = "asIs"
+	// PackageQualifiedSchemaNaming names the schema of order.Response "order.Response"
+	PackageQualifiedSchemaNaming SchemaNaming = "packageQualified"
+	// PackagePrefixedSchemaNaming names the schema of order.Response "OrderResponse"
+	PackagePrefixedSchemaNaming SchemaNaming = "packagePrefixed"
+)
+
+// ParseSchemaNaming returns the schema naming strategy with the name, where an empty name is the default strategy
+func ParseSchemaNaming(name string) (SchemaNaming, error) {
+	switch SchemaNaming(name) {
+	case "":
+		return LowerCamelSchemaNaming, nil
+	case LowerCamelSchemaNaming, AsIsSchemaNaming, PackageQualifiedSchemaNaming, PackagePrefixedSchemaNaming:
+		return SchemaNaming(name), nil
+	}
+	return "", fmt.Errorf("unknown schema naming %s", name)
+}
+
+// name returns the name of the schema of a type. The zero value of SchemaNaming names schemas in lower camel case
+func (n SchemaNaming) name(packageName string, typeName string) string {
+	switch n {
+	case AsIsSchemaNaming:
+		return typeName
+	case PackageQualifiedSchemaNaming:
+		return packageName + "." + typeName
+	case PackagePrefixedSchemaNaming:
+		return upper(packageName) + typeName
+	}
+	return lower(typeName)
+}
+
+// schemaName returns the name of the schema of a type declared in the package of the interpretation. A name that
+// follows the gopenapi:objectSchema annotation of the type, or the name in the YAML after it, takes precedence over
+// the naming strategy
+func (i *interpretation) schemaName(typeSpec *ast.TypeSpec, doc *ast.CommentGroup) string {
+	if a := objectSchemaAnnotation(doc); a != nil {
+		if len(a.arguments) != 0 {
+			return a.arguments[0]
+		}
+		if name := overriddenSchemaName(a); name != "" {
+			return name
+		}
+	}
+	return i.options.SchemaNaming.name(i.pkg.name, typeSpec.Name.Name)
+}
+
+// declarationSchemaName returns the name of the schema of a declared type
+func (i *interpretation) declarationSchemaName(declaration *typeDeclaration) string {
+	return i.inDeclaration(declaration).schemaName(declaration.spec, declaration.doc)
+}
+
+// schemaNames keeps the position of the type of which each schema has been generated, per specification
+type schemaNames map[*models.Root]map[string]token.Position
+
+// claimSchemaName claims the name for the schema of a type declared in the package of the interpretation, which is
+// identified by the name of its declaration. It reports whether the type already claimed the name, and fails when
+// another type did
+func (i *interpretation) claimSchemaName(name string, typeName *ast.Ident) (bool, error) {
+	positions, ok := i.names[i.root]
+	if !ok {
+		positions = map[string]token.Position{}
+		i.names[i.root] = positions
+	}
+	position := i.pkg.fileSet.Position(typeName.Pos())
+	claimedPosition, ok := positions[name]
+	if !ok {
+		positions[name] = position
+		return false, nil
+	}
+	if claimedPosition != position {
+		return false, fmt.Errorf("the schema of %s at %s and the schema of the type at %s are both named %s",
+			typeName.Name, position, claimedPosition, name)
+	}
+	return true, nil
+}
+
+func upper(s string) string {
+	a := []rune(s)
+	a[0] = unicode.ToUpper(a[0])
+	return string(a)
+}
@@ -0,0 +1,89 @@
+package interpret
+
+import (
+	"github.com/VanMoof/gopenapi/models"
+	"gopkg.in/yaml.v3"
+)
+
+// overrideSchema merges the YAML that follows the gopenapi:objectSchema annotation of a type over the schema that is
+// derived from the type. Objects like the properties are merged key by key, the required properties are added to the
+// ones of the schema, and anything else replaces what has been derived. The name of the schema is not part of it, and
+// the goTypes in it are resolved
+func (i *interpretation) overrideSchema(schema *models.Schema, a *annotation) error {
+	if a == nil || a.content == "" {
+		return nil
+	}
+	overrides := map[string]interface{}{}
+	err := a.decode(&overrides)
+	if err != nil {
+		return err
+	}
+	delete(overrides, "name")
+
+	derivedYAML, err := yaml.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	derived := map[string]interface{}{}
+	err = yaml.Unmarshal(derivedYAML, &derived)
+	if err != nil {
+		return err
+	}
+	mergeOverrides(derived, overrides)
+	mergedYAML, err := yaml.Marshal(derived)
+	if err != nil {
+		return err
+	}
+	*schema = models.Schema{}
+	err = yaml.Unmarshal(mergedYAML, schema)
+	if err != nil {
+		return err
+	}
+	return i.resolveGoTypes(schema, a)
+}
+
+// mergeOverrides merges the overrides into the values, where lists of required properties are joined and objects are
+// merged recursively
+func mergeOverrides(values map[string]interface{}, overrides map[string]interface{}) {
+	for key, override := range overrides {
+		switch override.(type) {
+		case map[string]interface{}:
+			if value, ok := values[key].(map[string]interface{}); ok {
+				mergeOverrides(value, override.(map[string]interface{}))
+				continue
+			}
+		case []interface{}:
+			if value, ok := values[key].([]interface{}); ok && key == "required" {
+				values[key] = joinRequired(value, override.([]interface{}))
+				continue
+			}
+		}
+		values[key] = override
+	}
+}
+
+// joinRequired adds the required properties that are not required yet
+func joinRequired(required []interface{}, additional []interface{}) []interface{} {
+	for _, a := range additional {
+		found := false
+		for _, r := range required {
+			found = found || r == a
+		}
+		if !found {
+			required = append(required, a)
+		}
+	}
+	return required
+}
+
+// overriddenSchemaName returns the name that the YAML that follows the annotation gives to the schema, or an empty
+// string when it gives none
+func overriddenSchemaName(a *annotation) string {
+	named := struct {
+		Name string `yaml:"name"`
+	}{}
+	if a.decode(&named) != nil {
+		return ""
+	}
+	return named.Name
+}
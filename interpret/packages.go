@@ -0,0 +1,325 @@
+package interpret
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// astPackage is a package of which all files have been parsed. The import path is only known when the package is part
+// of a module, and the type information only when the package has been loaded by the type checker
+type astPackage struct {
+	name             string
+	dir              string
+	path             string
+	fileSet          *token.FileSet
+	files            map[string]*ast.File
+	typeDeclarations map[string]*typeDeclaration
+	typesInfo        *types.Info
+}
+
+// typeDeclaration is the declaration of a named type in a package
+type typeDeclaration struct {
+	pkg  *astPackage
+	file *ast.File
+	spec *ast.TypeSpec
+	doc  *ast.CommentGroup
+}
+
+// packageResolver finds the packages that are imported by other packages
+type packageResolver interface {
+	// packageOfImport returns the package of the import path when it is part of the same module as the importing
+	// package, or nil when it is not
+	packageOfImport(importer *astPackage, importPath string) (*astPackage, error)
+	// modulePackages returns the package and the other packages of its module
+	modulePackages(pkg *astPackage) ([]*astPackage, error)
+}
+
+// packageIndex parses the packages of the module on demand and keeps them for the lifetime of the interpreter
+type packageIndex struct {
+	// buildTags are the build tags that are satisfied while matching the build constraints of files
+	buildTags []string
+	// packages are keyed by their directory and their name, since an interpreted file may be of another package than
+	// the files of its directory that satisfy their build constraints
+	packages map[string]*astPackage
+	// parsedDirs are the directories of which the files have been parsed
+	parsedDirs map[string]bool
+	// modules maps directories to the module they are part of
+	modules map[string]module
+}
+
+// module is a Go module, identified by its path and its root directory
+type module struct {
+	path string
+	dir  string
+}
+
+func packageKey(dir string, name string) string {
+	return dir + "#" + name
+}
+
+// packageOfFile returns the package that the file is part of, along with the parsed file. The file is part of it even
+// when it does not satisfy its build constraints, since it is interpreted on request
+func (p *packageIndex) packageOfFile(fileName string) (*astPackage, *ast.File, error) {
+	absoluteFileName, err := filepath.Abs(fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	dir := filepath.Dir(absoluteFileName)
+	if err := p.parseDir(dir); err != nil {
+		return nil, nil, err
+	}
+	for _, pkg := range p.packages {
+		if pkg.dir != dir {
+			continue
+		}
+		if file, ok := pkg.files[absoluteFileName]; ok {
+			return pkg, file, nil
+		}
+	}
+	pkg, file, err := p.parseFile(dir, absoluteFileName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse file %s: %w", fileName, err)
+	}
+	return pkg, file, nil
+}
+
+func (p *packageIndex) packageOfImport(importer *astPackage, importPath string) (*astPackage, error) {
+	m := p.moduleOf(importer.dir)
+	if !m.contains(importPath) {
+		return nil, nil
+	}
+	dir := filepath.Join(m.dir, filepath.FromSlash(strings.TrimPrefix(importPath, m.path)))
+	if err := p.parseDir(dir); err != nil {
+		return nil, err
+	}
+	for _, pkg := range p.packages {
+		if pkg.dir == dir && !strings.HasSuffix(pkg.name, "_test") {
+			return pkg, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *packageIndex) modulePackages(pkg *astPackage) ([]*astPackage, error) {
+	dirs, err := p.moduleDirs(pkg.dir)
+	if err != nil {
+		return nil, err
+	}
+	modulePackages := []*astPackage{pkg}
+	for _, dir := range dirs {
+		if err := p.parseDir(dir); err != nil {
+			return nil, err
+		}
+		for _, modulePackage := range p.packages {
+			if modulePackage.dir == dir && dir != pkg.dir && !strings.HasSuffix(modulePackage.name, "_test") {
+				modulePackages = append(modulePackages, modulePackage)
+			}
+		}
+	}
+	return modulePackages, nil
+}
+
+// moduleDirs returns the directories of the module of the directory that the go command does not ignore, along with
+// the ones below the directory itself, which may be ignored by the go command like testdata is
+func (p *packageIndex) moduleDirs(dir string) ([]string, error) {
+	var dirs []string
+	found := map[string]bool{}
+	walk := func(root string) error {
+		return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || !entry.IsDir() {
+				return err
+			}
+			if path != root {
+				name := entry.Name()
+				if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" {
+					return filepath.SkipDir
+				}
+				// A directory with a go.mod file is the root of another module
+				if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+					return filepath.SkipDir
+				}
+			}
+			if !found[path] {
+				found[path] = true
+				dirs = append(dirs, path)
+			}
+			return nil
+		})
+	}
+	if m := p.moduleOf(dir); m.dir != "" {
+		if err := walk(m.dir); err != nil {
+			return nil, err
+		}
+	}
+	if err := walk(dir); err != nil {
+		return nil, err
+	}
+	return dirs, nil
+}
+
+// parseDir parses the files of the directory that satisfy their build constraints, like the go command builds them.
+// Test files are left out
+func (p *packageIndex) parseDir(dir string) error {
+	if p.parsedDirs == nil {
+		p.parsedDirs = map[string]bool{}
+	}
+	if p.parsedDirs[dir] {
+		return nil
+	}
+	p.parsedDirs[dir] = true
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to parse the package in %s: %w", dir, err)
+	}
+	context := build.Default
+	context.BuildTags = p.buildTags
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if match, err := context.MatchFile(dir, name); err != nil || !match {
+			continue
+		}
+		if _, _, err := p.parseFile(dir, filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("failed to parse the package in %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// parseFile parses the file of the directory and adds it to the package that it declares
+func (p *packageIndex) parseFile(dir string, fileName string) (*astPackage, *ast.File, error) {
+	if p.packages == nil {
+		p.packages = map[string]*astPackage{}
+	}
+	// The files of a directory share a file set, like the packages in a directory share their files
+	var fileSet *token.FileSet
+	for _, pkg := range p.packages {
+		if pkg.dir == dir {
+			fileSet = pkg.fileSet
+		}
+	}
+	if fileSet == nil {
+		fileSet = token.NewFileSet()
+	}
+	file, err := parser.ParseFile(fileSet, fileName, nil, parser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkg, ok := p.packages[packageKey(dir, file.Name.Name)]
+	if !ok {
+		importPath := ""
+		if m := p.moduleOf(dir); m.path != "" {
+			relativeDir, err := filepath.Rel(m.dir, dir)
+			if err == nil {
+				importPath = path.Join(m.path, filepath.ToSlash(relativeDir))
+			}
+		}
+		pkg = &astPackage{
+			name:             file.Name.Name,
+			dir:              dir,
+			path:             importPath,
+			fileSet:          fileSet,
+			files:            map[string]*ast.File{},
+			typeDeclarations: map[string]*typeDeclaration{},
+		}
+		p.packages[packageKey(dir, file.Name.Name)] = pkg
+	}
+	pkg.files[fileName] = file
+	pkg.indexTypeDeclarations(file)
+	return pkg, file, nil
+}
+
+func (a *astPackage) indexTypeDeclarations(file *ast.File) {
+	for _, declaration := range file.Decls {
+		genDecl, ok := declaration.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if _, ok := a.typeDeclarations[typeSpec.Name.Name]; ok {
+				continue
+			}
+			a.typeDeclarations[typeSpec.Name.Name] = &typeDeclaration{
+				pkg:  a,
+				file: file,
+				spec: typeSpec,
+				doc:  typeSpecDoc(genDecl, typeSpec),
+			}
+		}
+	}
+}
+
+// typeSpecDoc returns the doc comment of a type specification, which is the one of the declaration unless the
+// declaration groups several types
+func typeSpecDoc(genDecl *ast.GenDecl, typeSpec *ast.TypeSpec) *ast.CommentGroup {
+	if genDecl.Lparen.IsValid() {
+		return typeSpec.Doc
+	}
+	return genDecl.Doc
+}
+
+// contains reports whether the import path is the one of a package of the module
+func (m module) contains(importPath string) bool {
+	return m.path != "" && (importPath == m.path || strings.HasPrefix(importPath, m.path+"/"))
+}
+
+var modulePattern = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+// moduleOf returns the module that the directory is part of, which is empty when the directory is not part of a
+// module
+func (p *packageIndex) moduleOf(dir string) module {
+	if p.modules == nil {
+		p.modules = map[string]module{}
+	}
+	if m, ok := p.modules[dir]; ok {
+		return m
+	}
+	m := module{}
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err == nil {
+		if match := modulePattern.FindSubmatch(goMod); match != nil {
+			m = module{path: strings.Trim(string(match[1]), `"`), dir: dir}
+		}
+	} else if parent := filepath.Dir(dir); parent != dir && os.IsNotExist(err) {
+		m = p.moduleOf(parent)
+	}
+	p.modules[dir] = m
+	return m
+}
+
+// importedPackage returns the package that the name refers to in the file, or nil when the package is not part of
+// the module
+func (a *astPackage) importedPackage(resolver packageResolver, file *ast.File, name string) (*astPackage, error) {
+	for _, importSpec := range file.Imports {
+		importPath, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		if importSpec.Name != nil && importSpec.Name.Name != name {
+			continue
+		}
+		pkg, err := resolver.packageOfImport(a, importPath)
+		if err != nil {
+			return nil, err
+		}
+		if pkg != nil && (importSpec.Name != nil || pkg.name == name) {
+			return pkg, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,173 @@
+package interpret
+
+import (
+	"fmt"
+	"github.com/VanMoof/gopenapi/models"
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PackagesInterpreter interprets files as part of their package, which is loaded with golang.org/x/tools/go/packages
+// along with its type information. Types are resolved by the type checker instead of by their names, so that named
+// types, aliases and imported types get the schema of what they really are. Files that are excluded from the build by
+// their build constraints are skipped
+type PackagesInterpreter struct {
+	Options
+	// BuildTags are the build tags that are satisfied while loading packages
+	BuildTags []string
+
+	fileSet  *token.FileSet
+	modules  packageIndex
+	packages map[string]*astPackage
+	names    schemaNames
+}
+
+func (p *PackagesInterpreter) InterpretFile(file *os.File, root *models.Root) error {
+	absoluteFileName, err := filepath.Abs(file.Name())
+	if err != nil {
+		return fmt.Errorf("failed to interpret file %s: %w", file.Name(), err)
+	}
+	pkg, err := p.load(filepath.Dir(absoluteFileName), ".")
+	if err != nil {
+		return fmt.Errorf("failed to interpret file %s: %w", file.Name(), err)
+	}
+	parsedFile, ok := pkg.files[absoluteFileName]
+	if !ok {
+		return nil
+	}
+	if p.names == nil {
+		p.names = schemaNames{}
+	}
+	i := &interpretation{options: &p.Options, resolver: p, names: p.names, pkg: pkg, file: parsedFile, root: root}
+	return i.interpretFile()
+}
+
+func (p *PackagesInterpreter) packageOfImport(importer *astPackage, importPath string) (*astPackage, error) {
+	if !p.modules.moduleOf(importer.dir).contains(importPath) {
+		return nil, nil
+	}
+	return p.load(importer.dir, importPath)
+}
+
+func (p *PackagesInterpreter) modulePackages(pkg *astPackage) ([]*astPackage, error) {
+	dirs, err := p.modules.moduleDirs(pkg.dir)
+	if err != nil {
+		return nil, err
+	}
+	modulePackages := []*astPackage{pkg}
+	for _, dir := range dirs {
+		if dir == pkg.dir {
+			continue
+		}
+		modulePackage, err := p.load(dir, ".")
+		if err != nil {
+			return nil, err
+		}
+		if len(modulePackage.files) != 0 {
+			modulePackages = append(modulePackages, modulePackage)
+		}
+	}
+	return modulePackages, nil
+}
+
+// load loads the package that matches the pattern in the directory. Packages are loaded once and are kept by both
+// their directory and their import path
+func (p *PackagesInterpreter) load(dir string, pattern string) (*astPackage, error) {
+	key := pattern
+	if pattern == "." {
+		key = dir
+	}
+	if pkg, ok := p.packages[key]; ok {
+		return pkg, nil
+	}
+	if p.packages == nil {
+		p.packages = map[string]*astPackage{}
+		p.fileSet = token.NewFileSet()
+	}
+
+	config := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports |
+			packages.NeedSyntax | packages.NeedExportFile,
+		Dir:  dir,
+		Fset: p.fileSet,
+	}
+	if len(p.BuildTags) != 0 {
+		config.BuildFlags = []string{"-tags=" + strings.Join(p.BuildTags, ",")}
+	}
+	loadedPackages, err := packages.Load(config, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the package %s in %s: %w", pattern, dir, err)
+	}
+	if len(loadedPackages) != 1 {
+		return nil, fmt.Errorf("failed to load the package %s in %s: found %d packages", pattern, dir, len(loadedPackages))
+	}
+	loadedPackage := loadedPackages[0]
+
+	pkg := &astPackage{
+		name:             loadedPackage.Name,
+		dir:              dir,
+		path:             loadedPackage.PkgPath,
+		fileSet:          p.fileSet,
+		files:            map[string]*ast.File{},
+		typeDeclarations: map[string]*typeDeclaration{},
+	}
+	// A package of which all files are excluded by their build constraints has no files to interpret
+	if len(loadedPackage.Syntax) != 0 {
+		if len(loadedPackage.Errors) != 0 {
+			return nil, fmt.Errorf("failed to load the package %s: %v", loadedPackage.PkgPath, loadedPackage.Errors[0])
+		}
+		pkg.typesInfo, err = p.typeCheck(loadedPackage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the package %s: %w", loadedPackage.PkgPath, err)
+		}
+		pkg.dir = filepath.Dir(loadedPackage.CompiledGoFiles[0])
+	}
+	for _, file := range loadedPackage.Syntax {
+		pkg.files[p.fileSet.Position(file.Pos()).Filename] = file
+		pkg.indexTypeDeclarations(file)
+	}
+	p.packages[key] = pkg
+	if pkg.path != "" && len(pkg.files) != 0 {
+		p.packages[pkg.path] = pkg
+		p.packages[pkg.dir] = pkg
+	}
+	return pkg, nil
+}
+
+// typeCheck type checks the syntax of the loaded package. Imported packages are read from the export data that the go
+// command builds for them, by the reader of the standard library, so that the export data is always read by the same
+// Go version that the go command has
+func (p *PackagesInterpreter) typeCheck(loadedPackage *packages.Package) (*types.Info, error) {
+	exportFiles := map[string]string{}
+	for importPath, importedPackage := range loadedPackage.Imports {
+		exportFiles[importPath] = importedPackage.ExportFile
+		exportFiles[importedPackage.PkgPath] = importedPackage.ExportFile
+	}
+	config := &types.Config{
+		Importer: importer.ForCompiler(p.fileSet, "gc", func(importPath string) (io.ReadCloser, error) {
+			exportFile, ok := exportFiles[importPath]
+			if !ok || exportFile == "" {
+				return nil, fmt.Errorf("no export data for %s", importPath)
+			}
+			return os.Open(exportFile)
+		}),
+	}
+	info := &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Instances:  map[*ast.Ident]types.Instance{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Implicits:  map[ast.Node]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+		Scopes:     map[ast.Node]*types.Scope{},
+	}
+	_, err := config.Check(loadedPackage.PkgPath, p.fileSet, loadedPackage.Syntax, info)
+	return info, err
+}
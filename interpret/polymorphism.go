@@ -0,0 +1,388 @@
+package interpret
+
+import (
+	"fmt"
+	"github.com/VanMoof/gopenapi/models"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// setSchemaOfInterface sets the schema of an interface type to a oneOf of the schemas of the types of the module that
+// implement it and that are annotated with gopenapi:objectSchema. When a method of the interface is annotated with
+// gopenapi:discriminator, the constants that the types return from it map the values of the discriminator to their
+// schemas
+func (i *interpretation) setSchemaOfInterface(schema *models.Schema, typeSpec *ast.TypeSpec, interfaceType *ast.InterfaceType) error {
+	*schema = models.Schema{Description: schema.Description, Deprecated: schema.Deprecated}
+	declaration := &typeDeclaration{pkg: i.pkg, file: i.file, spec: typeSpec}
+	implementers, err := i.implementersOf(declaration)
+	if err != nil {
+		return fmt.Errorf("failed to find the implementations of %s: %w", typeSpec.Name.Name, err)
+	}
+	if len(implementers) == 0 {
+		i.warn("no type of the module that is annotated with gopenapi:objectSchema implements %s at %s, so its schema "+
+			"is free-form", typeSpec.Name.Name, i.pkg.fileSet.Position(typeSpec.Pos()))
+		return nil
+	}
+	methodName, propertyName := discriminatorMethod(interfaceType)
+	if methodName != "" {
+		schema.Discriminator = &models.Discriminator{PropertyName: propertyName}
+	}
+	for _, implementer := range implementers {
+		implementerSchema := &models.Schema{}
+		err := i.referToDeclaration(implementerSchema, implementer)
+		if err != nil {
+			return err
+		}
+		schema.OneOf = append(schema.OneOf, implementerSchema)
+		if methodName == "" {
+			continue
+		}
+		err = i.includeDeclaration(implementer)
+		if err != nil {
+			return err
+		}
+		i.checkDiscriminatorProperty(implementer, propertyName, typeSpec.Name.Name)
+		value, ok := returnedConstant(implementer, methodName)
+		if !ok {
+			i.warn("the %s method of %s at %s does not return a constant, so it is left out of the mapping of the "+
+				"discriminator of %s", methodName, implementer.spec.Name.Name,
+				implementer.pkg.fileSet.Position(implementer.spec.Pos()), typeSpec.Name.Name)
+			continue
+		}
+		if schema.Discriminator.Mapping == nil {
+			schema.Discriminator.Mapping = map[string]string{}
+		}
+		schema.Discriminator.Mapping[value] = implementerSchema.Ref
+	}
+	return nil
+}
+
+// checkDiscriminatorProperty warns when the schema of an implementer of the interface lacks the property of the
+// discriminator or does not require it, since the discriminator cannot tell which schema a value has then. The
+// properties of a schema that is composed with a reference to another schema are not known, and are not checked
+func (i *interpretation) checkDiscriminatorProperty(implementer *typeDeclaration, propertyName string, interfaceName string) {
+	schema := i.root.Components.Schemas[i.declarationSchemaName(implementer)]
+	if schema == nil {
+		return
+	}
+	composed := false
+	for _, part := range append([]*models.Schema{schema}, schema.AllOf...) {
+		if part.Ref != "" {
+			composed = true
+			continue
+		}
+		if _, ok := part.Properties[propertyName]; !ok {
+			continue
+		}
+		for _, required := range part.Required {
+			if required == propertyName {
+				return
+			}
+		}
+		i.warn("the property %s of the discriminator of %s is not required by %s at %s", propertyName, interfaceName,
+			implementer.spec.Name.Name, implementer.pkg.fileSet.Position(implementer.spec.Pos()))
+		return
+	}
+	if !composed {
+		i.warn("the property %s of the discriminator of %s is missing from %s at %s", propertyName, interfaceName,
+			implementer.spec.Name.Name, implementer.pkg.fileSet.Position(implementer.spec.Pos()))
+	}
+}
+
+// discriminatorMethod returns the name of the method of the interface that is annotated with gopenapi:discriminator,
+// and the name of the property of the discriminator. The property is named after the method, unless a name follows
+// the annotation
+func discriminatorMethod(interfaceType *ast.InterfaceType) (string, string) {
+	for _, method := range interfaceType.Methods.List {
+		if len(method.Names) == 0 {
+			continue
+		}
+		for _, a := range annotationsOf(method.Doc) {
+			if a.keyword != "gopenapi:discriminator" {
+				continue
+			}
+			if len(a.arguments) != 0 {
+				return method.Names[0].Name, a.arguments[0]
+			}
+			return method.Names[0].Name, lower(method.Names[0].Name)
+		}
+	}
+	return "", ""
+}
+
+// implementersOf returns the declarations of the types of the module that implement the interface and that are
+// annotated with gopenapi:objectSchema, in the order of the names of their schemas
+func (i *interpretation) implementersOf(declaration *typeDeclaration) ([]*typeDeclaration, error) {
+	interfaceMethods, err := i.methodsOf(declaration, map[*ast.TypeSpec]bool{})
+	if err != nil || len(interfaceMethods) == 0 {
+		return nil, err
+	}
+	modulePackages, err := i.resolver.modulePackages(declaration.pkg)
+	if err != nil {
+		return nil, err
+	}
+	var implementers []*typeDeclaration
+	for _, pkg := range modulePackages {
+		for _, candidate := range pkg.typeDeclarations {
+			if _, isInterface := candidate.spec.Type.(*ast.InterfaceType); isInterface || candidate.spec.TypeParams != nil {
+				continue
+			}
+			if !isObjectSchema(candidate.doc) {
+				continue
+			}
+			methods, err := i.methodsOf(candidate, map[*ast.TypeSpec]bool{})
+			if err != nil {
+				return nil, err
+			}
+			if hasMethods(methods, interfaceMethods) {
+				implementers = append(implementers, candidate)
+			}
+		}
+	}
+	sort.Slice(implementers, func(a, b int) bool {
+		return i.declarationSchemaName(implementers[a]) < i.declarationSchemaName(implementers[b])
+	})
+	return implementers, nil
+}
+
+// hasMethods reports whether the methods include all the methods of the interface
+func hasMethods(methods map[string]string, interfaceMethods map[string]string) bool {
+	for name, signature := range interfaceMethods {
+		if candidateSignature, ok := methods[name]; !ok || candidateSignature != signature {
+			return false
+		}
+	}
+	return true
+}
+
+// methodsOf returns the signatures of the methods of the declared type, or of a pointer to it, by their names. The
+// names of unexported methods are qualified by their package, so that only the types of that package implement an
+// interface that has them. Without type information the signatures are derived from the syntax, and the methods are
+// those declared for the type, those of the interfaces that it embeds and those of the structs that it embeds
+func (i *interpretation) methodsOf(declaration *typeDeclaration, visited map[*ast.TypeSpec]bool) (map[string]string, error) {
+	methods := map[string]string{}
+	if declaration.pkg.typesInfo != nil {
+		typeName, ok := declaration.pkg.typesInfo.Defs[declaration.spec.Name].(*types.TypeName)
+		if !ok {
+			return methods, nil
+		}
+		t := typeName.Type()
+		if _, isInterface := t.Underlying().(*types.Interface); !isInterface {
+			t = types.NewPointer(t)
+		}
+		methodSet := types.NewMethodSet(t)
+		for m := 0; m < methodSet.Len(); m++ {
+			method := methodSet.At(m).Obj()
+			pkgPath := ""
+			if method.Pkg() != nil {
+				pkgPath = method.Pkg().Path()
+			}
+			methods[methodKey(method.Name(), pkgPath)] = types.TypeString(method.Type(), func(p *types.Package) string {
+				return p.Path()
+			})
+		}
+		return methods, nil
+	}
+
+	if visited[declaration.spec] {
+		return methods, nil
+	}
+	visited[declaration.spec] = true
+	d := i.inDeclaration(declaration)
+	var embeddedTypes []ast.Expr
+	switch declaration.spec.Type.(type) {
+	case *ast.InterfaceType:
+		for _, method := range declaration.spec.Type.(*ast.InterfaceType).Methods.List {
+			if len(method.Names) == 0 {
+				embeddedTypes = append(embeddedTypes, method.Type)
+			}
+			for _, name := range method.Names {
+				methods[methodKey(name.Name, declaration.pkg.dir)] = d.signatureOf(method.Type.(*ast.FuncType))
+			}
+		}
+	case *ast.StructType:
+		for _, field := range declaration.spec.Type.(*ast.StructType).Fields.List {
+			if len(field.Names) == 0 {
+				embeddedTypes = append(embeddedTypes, field.Type)
+			}
+		}
+	}
+	for _, file := range declaration.pkg.sortedFiles() {
+		f := i.inDeclaration(&typeDeclaration{pkg: declaration.pkg, file: file, spec: declaration.spec})
+		for _, fileDeclaration := range file.Decls {
+			funcDecl, ok := fileDeclaration.(*ast.FuncDecl)
+			if ok && receiverTypeName(funcDecl) == declaration.spec.Name.Name {
+				methods[methodKey(funcDecl.Name.Name, declaration.pkg.dir)] = f.signatureOf(funcDecl.Type)
+			}
+		}
+	}
+	for _, embeddedType := range embeddedTypes {
+		if starExpr, ok := embeddedType.(*ast.StarExpr); ok {
+			embeddedType = starExpr.X
+		}
+		embeddedDeclaration, err := d.declarationByName(embeddedType)
+		if err != nil {
+			return nil, err
+		}
+		if embeddedDeclaration == nil {
+			continue
+		}
+		embeddedMethods, err := d.methodsOf(embeddedDeclaration, visited)
+		if err != nil {
+			return nil, err
+		}
+		for name, signature := range embeddedMethods {
+			methods[name] = signature
+		}
+	}
+	return methods, nil
+}
+
+// signatureOf returns the signature of a function type without type information, in which named types are qualified
+// by the import paths of their packages, like a signature resolved by the type checker is
+func (i *interpretation) signatureOf(funcType *ast.FuncType) string {
+	results := i.typeStrings(funcType.Results)
+	signature := "func(" + strings.Join(i.typeStrings(funcType.Params), ", ") + ")"
+	switch len(results) {
+	case 0:
+		return signature
+	case 1:
+		return signature + " " + results[0]
+	}
+	return signature + " (" + strings.Join(results, ", ") + ")"
+}
+
+// typeStrings returns the types of the fields of a parameter or result list, once for each name of a field
+func (i *interpretation) typeStrings(fields *ast.FieldList) []string {
+	if fields == nil {
+		return nil
+	}
+	var typeStrings []string
+	for _, field := range fields.List {
+		typeString := i.typeString(field.Type)
+		typeStrings = append(typeStrings, typeString)
+		for n := 1; n < len(field.Names); n++ {
+			typeStrings = append(typeStrings, typeString)
+		}
+	}
+	return typeStrings
+}
+
+// typeString returns a type expression in which named types are qualified by the import paths of their packages
+func (i *interpretation) typeString(typeExpr ast.Expr) string {
+	switch typeExpr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		return i.qualifiedTypeName(typeExpr)
+	case *ast.ParenExpr:
+		return i.typeString(typeExpr.(*ast.ParenExpr).X)
+	case *ast.StarExpr:
+		return "*" + i.typeString(typeExpr.(*ast.StarExpr).X)
+	case *ast.Ellipsis:
+		return "..." + i.typeString(typeExpr.(*ast.Ellipsis).Elt)
+	case *ast.ArrayType:
+		arrayType := typeExpr.(*ast.ArrayType)
+		if arrayType.Len == nil {
+			return "[]" + i.typeString(arrayType.Elt)
+		}
+		return "[" + types.ExprString(arrayType.Len) + "]" + i.typeString(arrayType.Elt)
+	case *ast.MapType:
+		mapType := typeExpr.(*ast.MapType)
+		return "map[" + i.typeString(mapType.Key) + "]" + i.typeString(mapType.Value)
+	}
+	return types.ExprString(typeExpr)
+}
+
+// methodKey returns the name of a method, qualified by its package when it is unexported
+func methodKey(name string, pkg string) string {
+	if token.IsExported(name) {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// receiverTypeName returns the name of the type of the receiver of a method, or an empty string for a function
+func receiverTypeName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+	typeExpr := funcDecl.Recv.List[0].Type
+	if starExpr, ok := typeExpr.(*ast.StarExpr); ok {
+		typeExpr = starExpr.X
+	}
+	if ident, ok := withoutTypeArguments(typeExpr).(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// returnedConstant returns the constant that the method of the declared type returns, when its body does nothing but
+// return a constant
+func returnedConstant(declaration *typeDeclaration, methodName string) (string, bool) {
+	for _, file := range declaration.pkg.sortedFiles() {
+		for _, fileDeclaration := range file.Decls {
+			funcDecl, ok := fileDeclaration.(*ast.FuncDecl)
+			if !ok || funcDecl.Name.Name != methodName || receiverTypeName(funcDecl) != declaration.spec.Name.Name {
+				continue
+			}
+			if funcDecl.Body == nil || len(funcDecl.Body.List) != 1 {
+				return "", false
+			}
+			returnStmt, ok := funcDecl.Body.List[0].(*ast.ReturnStmt)
+			if !ok || len(returnStmt.Results) != 1 {
+				return "", false
+			}
+			value, ok := declaration.pkg.constantOfExpr(returnStmt.Results[0])
+			if !ok {
+				return "", false
+			}
+			if value.Kind() == constant.String {
+				return constant.StringVal(value), true
+			}
+			return value.ExactString(), true
+		}
+	}
+	return "", false
+}
+
+// constantOfExpr evaluates an expression that is a literal or that refers to a constant of the package.
+// Without type information, the constants of other packages are not resolved
+func (a *astPackage) constantOfExpr(expr ast.Expr) (constant.Value, bool) {
+	if a.typesInfo != nil {
+		value := a.typesInfo.Types[expr].Value
+		return value, value != nil
+	}
+	switch expr.(type) {
+	case *ast.Ident:
+		if value, ok := constantValue(expr, 0); ok {
+			return value, true
+		}
+		return a.constantNamed(expr.(*ast.Ident).Name)
+	case *ast.CallExpr:
+		callExpr := expr.(*ast.CallExpr)
+		if len(callExpr.Args) == 1 {
+			return a.constantOfExpr(callExpr.Args[0])
+		}
+	case *ast.ParenExpr:
+		return a.constantOfExpr(expr.(*ast.ParenExpr).X)
+	}
+	return constantValue(expr, 0)
+}
+
+// constantNamed evaluates the constant of the package with the name, when its value is made up of literals, iota and
+// operators
+func (a *astPackage) constantNamed(name string) (constant.Value, bool) {
+	var value constant.Value
+	found := false
+	a.eachConstant(func(c *packageConstant) bool {
+		if c.name.Name != name || c.valueExpr == nil {
+			return true
+		}
+		value, found = constantValue(c.valueExpr, c.iota)
+		return false
+	})
+	return value, found
+}
@@ -0,0 +1,1012 @@
+package interpret
+
+import (
+	"errors"
+	"fmt"
+	"github.com/VanMoof/gopenapi/models"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func (i *interpretation) openAPIBlockFromTypeSpec(typeSpec *ast.TypeSpec, doc *ast.CommentGroup) error {
+	if i.root.Components == nil {
+		i.root.Components = &models.Components{}
+	}
+	if i.root.Components.Schemas == nil {
+		i.root.Components.Schemas = map[string]*models.Schema{}
+	}
+
+	newSchema := &models.Schema{
+		Type:       "object",
+		Properties: map[string]*models.Schema{},
+	}
+	newSchemaName := i.schemaName(typeSpec, doc)
+	if _, err := i.claimSchemaName(newSchemaName, typeSpec.Name); err != nil {
+		return err
+	}
+	describeSchema(newSchema, commentText(doc))
+
+	i.root.Components.Schemas[newSchemaName] = newSchema
+	err := i.deriveSchemaOfTypeSpec(newSchema, typeSpec, doc)
+	var unsupported *unsupportedTypeError
+	if errors.As(err, &unsupported) {
+		i.warn("skipping type %s at %s: %v", typeSpec.Name.Name, i.pkg.fileSet.Position(typeSpec.Pos()), err)
+		delete(i.root.Components.Schemas, newSchemaName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	err = i.overrideSchema(newSchema, objectSchemaAnnotation(doc))
+	if err != nil {
+		return fmt.Errorf("failed to override the schema of %s: %w", typeSpec.Name.Name, err)
+	}
+	return nil
+}
+
+// deriveSchemaOfTypeSpec derives the schema of a declared type from the type
+func (i *interpretation) deriveSchemaOfTypeSpec(schema *models.Schema, typeSpec *ast.TypeSpec, doc *ast.CommentGroup) error {
+	if interfaceType, isInterface := typeSpec.Type.(*ast.InterfaceType); isInterface {
+		return i.setSchemaOfInterface(schema, typeSpec, interfaceType)
+	}
+	if i.pkg.typesInfo != nil && i.schemaOfMarshalerDeclaration(schema, typeSpec, doc) {
+		return nil
+	}
+	if structType, isStruct := typeSpec.Type.(*ast.StructType); isStruct {
+		err := i.schemaFieldsFromStructType(structType, schema)
+		if err != nil {
+			return fmt.Errorf("failed to resolve the fields of %s: %w", typeSpec.Name.Name, err)
+		}
+		composeAllOf(schema)
+		return nil
+	}
+	schema.Type = ""
+	schema.Properties = nil
+	err := i.setSchemaTypeOfTypeSpec(schema, typeSpec)
+	var unsupported *unsupportedTypeError
+	if err != nil && !errors.As(err, &unsupported) {
+		return fmt.Errorf("failed to resolve the type of %s: %w", typeSpec.Name.Name, err)
+	}
+	return err
+}
+
+// setSchemaTypeOfTypeSpec sets the type of the schema of a declared type that is not a struct to the one of the type
+// that it is based on. A type based on a string or a number gets the values of its constants as enum
+func (i *interpretation) setSchemaTypeOfTypeSpec(schema *models.Schema, typeSpec *ast.TypeSpec) error {
+	if i.pkg.typesInfo != nil {
+		if typeName, ok := i.pkg.typesInfo.Defs[typeSpec.Name].(*types.TypeName); ok {
+			i.markInlined(typeName)
+			defer delete(i.inlinedTypes, typeName)
+		}
+		underlying := i.pkg.typesInfo.TypeOf(typeSpec.Type).Underlying()
+		err := i.setSchemaTypeOfType(schema, underlying)
+		if _, isBasic := underlying.(*types.Basic); isBasic && err == nil {
+			i.enumFromConstants(typeSpec.Name.Name, schema)
+		}
+		return err
+	}
+	err := i.setSchemaTypeOfExpr(schema, typeSpec.Type)
+	if _, isIdent := typeSpec.Type.(*ast.Ident); isIdent && schema.Ref == "" && err == nil {
+		i.enumFromConstants(typeSpec.Name.Name, schema)
+	}
+	return err
+}
+
+// structFieldNames returns the names of the properties of the struct field, like encoding/json or gopkg.in/yaml.v3
+// names them. Each exported name of the field, like both names of A, B int, is a property that is named after the tag
+// of the field or else after the name of the field itself
+func (i *interpretation) structFieldNames(structField *ast.Field) []string {
+	if structTag(structField).Get(i.options.NameTag.key()) == "-" {
+		return nil
+	}
+	tagName, _ := i.nameTag(structField)
+	goNames := []string{embeddedTypeName(structField)}
+	if len(structField.Names) != 0 {
+		goNames = nil
+		for _, name := range structField.Names {
+			goNames = append(goNames, name.Name)
+		}
+	}
+	var fieldNames []string
+	for _, goName := range goNames {
+		if !ast.IsExported(goName) {
+			continue
+		}
+		fieldNames = append(fieldNames, i.options.NameTag.fieldName(tagName, goName))
+	}
+	return fieldNames
+}
+
+// quoteScalar turns the schema of a number or a boolean into the one of a string, as encoding/json quotes them when
+// their json tag has the string option. The format of numbers is kept to tell which numbers the strings are, the values
+// of the enum are quoted as well and the constraints that only apply to numbers are dropped. A reference is left as it
+// is, as the schema that it refers to is shared with the fields that are not quoted
+func quoteScalar(schema *models.Schema) {
+	if schema.Ref != "" {
+		return
+	}
+	switch schema.Type {
+	case "integer", "number", "boolean":
+	default:
+		return
+	}
+	schema.Type = "string"
+	schema.MultipleOf = nil
+	schema.Minimum = nil
+	schema.ExclusiveMinimum = false
+	schema.Maximum = nil
+	schema.ExclusiveMaximum = false
+	for n, value := range schema.Enum {
+		schema.Enum[n] = fmt.Sprint(value)
+	}
+}
+
+// isValidTagName reports whether encoding/json accepts the name in a json tag, and otherwise ignores it
+func isValidTagName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c) && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// structTag returns the tag of the struct field, which is empty when the field has none
+func structTag(structField *ast.Field) reflect.StructTag {
+	if structField.Tag == nil {
+		return ""
+	}
+	unquoted, err := strconv.Unquote(structField.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(unquoted)
+}
+
+// embeddedTypeName returns the name of the type of an embedded struct field, without the package qualifier and the
+// type arguments
+func embeddedTypeName(structField *ast.Field) string {
+	typeExpr := structField.Type
+	if starExpr, ok := typeExpr.(*ast.StarExpr); ok {
+		typeExpr = starExpr.X
+	}
+	typeExpr = withoutTypeArguments(typeExpr)
+	switch typeExpr.(type) {
+	case *ast.Ident:
+		return typeExpr.(*ast.Ident).Name
+	case *ast.SelectorExpr:
+		return typeExpr.(*ast.SelectorExpr).Sel.Name
+	}
+	return ""
+}
+
+// withoutTypeArguments returns the generic type of the instantiation of a generic type, like Page of Page[Order], and
+// any other type expression as it is
+func withoutTypeArguments(typeExpr ast.Expr) ast.Expr {
+	switch typeExpr.(type) {
+	case *ast.IndexExpr:
+		return typeExpr.(*ast.IndexExpr).X
+	case *ast.IndexListExpr:
+		return typeExpr.(*ast.IndexListExpr).X
+	}
+	return typeExpr
+}
+
+// isPromotingEmbeddedField reports whether the struct field is an embedded struct of which the fields are promoted.
+// encoding/json treats an embedded field with a name in its tag like any other field, and gopkg.in/yaml.v3 only
+// promotes the fields of a struct with the inline option
+func (i *interpretation) isPromotingEmbeddedField(structField *ast.Field) bool {
+	tagName, tagOptions := i.nameTag(structField)
+	if i.options.NameTag == YAMLNameTag {
+		return hasOption(tagOptions, "inline")
+	}
+	return len(structField.Names) == 0 && !isValidTagName(tagName)
+}
+
+// schemaFieldsFromStructType adds the fields of the struct to the properties of the schema, along with the fields that
+// embedded structs promote. Like encoding/json does, the structs are visited breadth first and each struct only once,
+// and of the fields with the same name the shallowest one wins. Of equally shallow fields a tagged one wins, and
+// otherwise none of them does
+func (i *interpretation) schemaFieldsFromStructType(structType *ast.StructType, newSchema *models.Schema) error {
+	for _, structField := range structType.Fields.List {
+		if len(structField.Names) == 1 && structField.Names[0].Name == "XMLName" {
+			schemaXMLFromXMLName(structField, newSchema)
+		}
+	}
+	var names []string
+	candidates := map[string][]promotedProperty{}
+	current := []*embeddedStruct{{interpretation: i, structType: structType, count: 1}}
+	visited := map[*ast.StructType]bool{}
+	for depth := 0; len(current) != 0; depth++ {
+		var next []*embeddedStruct
+		nextByStruct := map[*ast.StructType]*embeddedStruct{}
+		for _, embedded := range current {
+			if visited[embedded.structType] {
+				continue
+			}
+			visited[embedded.structType] = true
+			e := embedded.interpretation
+			for _, structField := range embedded.structType.Fields.List {
+				if e.isPromotingEmbeddedField(structField) {
+					nextEmbedded, err := e.embeddedStructOf(structField, newSchema)
+					if err != nil {
+						return err
+					}
+					if nextEmbedded == nil {
+						continue
+					}
+					if nextEmbedded.structType != nil {
+						nextEmbedded.viaPointer = nextEmbedded.viaPointer || embedded.viaPointer
+						if existing, ok := nextByStruct[nextEmbedded.structType]; ok {
+							existing.count++
+						} else {
+							nextByStruct[nextEmbedded.structType] = nextEmbedded
+							next = append(next, nextEmbedded)
+						}
+						continue
+					}
+				}
+				tagName, _ := e.nameTag(structField)
+				for _, fieldName := range e.structFieldNames(structField) {
+					fieldSchema := &models.Schema{Properties: map[string]*models.Schema{}}
+					err := e.schemaFieldFromStructField(structField, fieldName, fieldSchema)
+					if err != nil {
+						return err
+					}
+					property, ok := fieldSchema.Properties[fieldName]
+					if !ok {
+						continue
+					}
+					if _, ok := candidates[fieldName]; !ok {
+						names = append(names, fieldName)
+					}
+					candidate := promotedProperty{
+						schema:   property,
+						depth:    depth,
+						tagged:   isValidTagName(tagName),
+						required: len(fieldSchema.Required) != 0 && !embedded.viaPointer,
+					}
+					// A struct that is embedded more than once at the same depth makes its fields ambiguous
+					for c := 0; c < embedded.count && c < 2; c++ {
+						candidates[fieldName] = append(candidates[fieldName], candidate)
+					}
+				}
+			}
+		}
+		current = next
+	}
+	for _, name := range names {
+		dominant, ok := dominantProperty(candidates[name])
+		if !ok {
+			continue
+		}
+		newSchema.Properties[name] = dominant.schema
+		if dominant.required {
+			addRequired(newSchema, name)
+		}
+	}
+	return nil
+}
+
+// embeddedStruct is a struct of which the fields are promoted to the struct that embeds it. It is embedded through a
+// pointer when any of the structs that embed it is, and its count is the number of times it is embedded at its depth
+type embeddedStruct struct {
+	interpretation *interpretation
+	structType     *ast.StructType
+	viaPointer     bool
+	count          int
+}
+
+// promotedProperty is a candidate for a property of a struct, which is promoted from the depth of an embedded struct
+type promotedProperty struct {
+	schema   *models.Schema
+	depth    int
+	tagged   bool
+	required bool
+}
+
+// dominantProperty returns the property that wins of the candidates with the same name, like encoding/json chooses
+// between the fields with the same name. It reports whether any of them wins
+func dominantProperty(candidates []promotedProperty) (promotedProperty, bool) {
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if candidates[a].depth != candidates[b].depth {
+			return candidates[a].depth < candidates[b].depth
+		}
+		return candidates[a].tagged && !candidates[b].tagged
+	})
+	if len(candidates) > 1 && candidates[0].depth == candidates[1].depth && candidates[0].tagged == candidates[1].tagged {
+		return promotedProperty{}, false
+	}
+	return candidates[0], true
+}
+
+func (i *interpretation) schemaFieldFromStructField(structField *ast.Field, fieldName string, newSchema *models.Schema) error {
+	newSchema.Properties[fieldName] = &models.Schema{}
+	fieldComment := commentText(structField.Doc)
+	if prose(fieldComment) == "" {
+		fieldComment = commentText(structField.Comment)
+	}
+	describeSchema(newSchema.Properties[fieldName], fieldComment)
+	var err error
+	if a := objectSchemaAnnotation(structField.Doc); a != nil {
+		err = i.hoistStructField(newSchema.Properties[fieldName], structField, fieldName, a)
+	} else {
+		err = i.setSchemaTypeOfExpr(newSchema.Properties[fieldName], structField.Type)
+	}
+	var unsupported *unsupportedTypeError
+	if errors.As(err, &unsupported) {
+		i.warn("skipping field %s at %s: %v", fieldName, i.pkg.fileSet.Position(structField.Pos()), err)
+		delete(newSchema.Properties, fieldName)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve the type of field %s: %w", fieldName, err)
+	}
+	schemaValidationFromStructField(structField, newSchema, fieldName)
+	_, tagOptions := i.nameTag(structField)
+	if i.options.NameTag != YAMLNameTag && hasOption(tagOptions, "string") {
+		quoteScalar(newSchema.Properties[fieldName])
+	}
+	schemaXMLFromStructField(structField, newSchema.Properties[fieldName])
+	openAPIOptions := openAPITag(structField)
+	schemaPresenceFromStructField(structField, newSchema, fieldName, tagOptions, openAPIOptions)
+	err = i.schemaOptionsFromStructField(structField, newSchema.Properties[fieldName], openAPIOptions)
+	if err != nil {
+		return fmt.Errorf("failed to override the schema of field %s: %w", fieldName, err)
+	}
+	nullableRef(newSchema.Properties[fieldName])
+	return nil
+}
+
+// nullableRef moves the reference of a nullable schema into an allOf, as OpenAPI 3.0 ignores the properties that are
+// next to a reference
+func nullableRef(schema *models.Schema) {
+	if !schema.Nullable || schema.Ref == "" {
+		return
+	}
+	schema.AllOf = []*models.Schema{{Ref: schema.Ref}}
+	schema.Ref = ""
+}
+
+// setSchemaTypeOfExpr sets the type of the schema to the one of the type expression. The type checker resolves the
+// type when the package has been loaded with type information, otherwise the type is derived from its name
+func (i *interpretation) setSchemaTypeOfExpr(schema *models.Schema, typeExpr ast.Expr) error {
+	// An anonymous struct is built from its syntax, so the type checker only resolves the types that surround it
+	if i.pkg.typesInfo != nil && !containsStructType(typeExpr) {
+		return i.setSchemaTypeOfType(schema, i.typeOf(typeExpr))
+	}
+	switch typeExpr.(type) {
+	case *ast.ParenExpr:
+		return i.setSchemaTypeOfExpr(schema, typeExpr.(*ast.ParenExpr).X)
+	case *ast.StarExpr:
+		return i.setSchemaTypeOfExpr(schema, typeExpr.(*ast.StarExpr).X)
+	case *ast.SelectorExpr:
+		selectorExpr := typeExpr.(*ast.SelectorExpr)
+		name := types.ExprString(selectorExpr)
+		if name == "unsafe.Pointer" {
+			return &unsupportedTypeError{typeName: name}
+		}
+		return i.setSchemaTypeOfName(schema, name, typeExpr)
+	case *ast.Ident:
+		name := typeExpr.(*ast.Ident).Name
+		// A predeclared type is known without the type checker, unless the package declares a type of the same name
+		if predeclared, ok := types.Universe.Lookup(name).(*types.TypeName); ok && i.pkg.typeDeclarations[name] == nil {
+			return i.setSchemaTypeOfType(schema, predeclared.Type())
+		}
+		return i.setSchemaTypeOfName(schema, name, typeExpr)
+	case *ast.IndexExpr:
+		return i.setSchemaTypeOfExpr(schema, typeExpr.(*ast.IndexExpr).X)
+	case *ast.IndexListExpr:
+		return i.setSchemaTypeOfExpr(schema, typeExpr.(*ast.IndexListExpr).X)
+	case *ast.ArrayType:
+		arrayType := typeExpr.(*ast.ArrayType)
+		elementName, ok := arrayType.Elt.(*ast.Ident)
+		isByteSlice := ok && arrayType.Len == nil && (elementName.Name == "byte" || elementName.Name == "uint8")
+		return setSchemaTypeOfList(schema, isByteSlice, func(items *models.Schema) error {
+			return i.setSchemaTypeOfExpr(items, arrayType.Elt)
+		})
+	case *ast.MapType:
+		return setSchemaTypeOfMap(schema, func(values *models.Schema) error {
+			return i.setSchemaTypeOfExpr(values, typeExpr.(*ast.MapType).Value)
+		})
+	case *ast.StructType:
+		schema.Type = "object"
+		schema.Properties = map[string]*models.Schema{}
+		err := i.schemaFieldsFromStructType(typeExpr.(*ast.StructType), schema)
+		if err != nil {
+			return err
+		}
+		composeAllOf(schema)
+	case *ast.InterfaceType:
+		return i.setSchemaTypeOfType(schema, types.NewInterfaceType(nil, nil))
+	default:
+		return &unsupportedTypeError{typeName: types.ExprString(typeExpr)}
+	}
+	return nil
+}
+
+// containsStructType reports whether the type expression contains an anonymous struct
+func containsStructType(typeExpr ast.Expr) bool {
+	found := false
+	ast.Inspect(typeExpr, func(node ast.Node) bool {
+		if _, isStruct := node.(*ast.StructType); isStruct {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// hoistStructField generates the schema of the anonymous struct of a field that is annotated with
+// gopenapi:objectSchema as a schema of the components, which the property refers to. The schema is named after the
+// field, unless a name follows the annotation, and the YAML that follows the annotation is merged over it
+func (i *interpretation) hoistStructField(schema *models.Schema, structField *ast.Field, fieldName string, a *annotation) error {
+	typeExpr := structField.Type
+	if starExpr, ok := typeExpr.(*ast.StarExpr); ok {
+		typeExpr = starExpr.X
+	}
+	structType, ok := typeExpr.(*ast.StructType)
+	if !ok || len(structField.Names) == 0 {
+		return fmt.Errorf("%s of field %s at %s should annotate a field of an anonymous struct", a.keyword, fieldName,
+			i.pkg.fileSet.Position(structField.Pos()))
+	}
+	hoistedSchemaName := i.options.SchemaNaming.name(i.pkg.name, structField.Names[0].Name)
+	if len(a.arguments) != 0 {
+		hoistedSchemaName = a.arguments[0]
+	}
+	if _, err := i.claimSchemaName(hoistedSchemaName, structField.Names[0]); err != nil {
+		return err
+	}
+	hoistedSchema := &models.Schema{}
+	i.root.Components.Schemas[hoistedSchemaName] = hoistedSchema
+	schema.Ref = "#/components/schemas/" + hoistedSchemaName
+	err := i.setSchemaTypeOfExpr(hoistedSchema, structType)
+	if err != nil {
+		return err
+	}
+	return i.overrideSchema(hoistedSchema, a)
+}
+
+// unsupportedTypeError is the error of a type that encoding/json cannot marshal, like a channel or a function
+type unsupportedTypeError struct {
+	typeName string
+}
+
+func (u *unsupportedTypeError) Error() string {
+	return fmt.Sprintf("encoding/json does not support the type %s", u.typeName)
+}
+
+// setSchemaTypeOfName sets the type of the schema to the one of a named type. A type that is declared in the module is
+// referred to by the name of its declaration, and any other type by the name that the schema naming gives it
+func (i *interpretation) setSchemaTypeOfName(schema *models.Schema, name string, typeExpr ast.Expr) error {
+	if mapped, err := i.setMappedSchema(schema, i.qualifiedTypeName(typeExpr)); mapped || err != nil {
+		return err
+	}
+	setSchemaType(schema, name)
+	if schema.Ref == "" {
+		return nil
+	}
+	declaration, err := i.typeDeclarationOf(typeExpr)
+	if err != nil {
+		return err
+	}
+	if declaration == nil {
+		if i.resolvingGoType {
+			return fmt.Errorf("type %s is not declared in the module", name)
+		}
+		packageName, typeName := i.pkg.name, name
+		if selectorExpr, ok := typeExpr.(*ast.SelectorExpr); ok {
+			packageName, typeName = types.ExprString(selectorExpr.X), selectorExpr.Sel.Name
+		}
+		schema.Ref = "#/components/schemas/" + i.options.SchemaNaming.name(packageName, typeName)
+		return nil
+	}
+	return i.referToDeclaration(schema, declaration)
+}
+
+// referToDeclaration sets the schema to a reference to the schema of the declared type. When the interpreter includes
+// referenced types or a goType refers to the type, the schema of the type is generated unless it already exists. Since
+// a schema exists as soon as its generation starts, the generation of recursive types ends
+func (i *interpretation) referToDeclaration(schema *models.Schema, declaration *typeDeclaration) error {
+	schema.Ref = "#/components/schemas/" + i.declarationSchemaName(declaration)
+	if !i.options.IncludeReferencedTypes && !i.resolvingGoType {
+		return nil
+	}
+	return i.includeDeclaration(declaration)
+}
+
+// includeDeclaration generates the schema of the declared type, unless it already exists
+func (i *interpretation) includeDeclaration(declaration *typeDeclaration) error {
+	d := i.inDeclaration(declaration)
+	exists, err := d.claimSchemaName(d.schemaName(declaration.spec, declaration.doc), declaration.spec.Name)
+	if err != nil || exists {
+		return err
+	}
+	return d.openAPIBlockFromTypeSpec(declaration.spec, declaration.doc)
+}
+
+// setSchemaTypeOfType sets the type of the schema to the one of a type resolved by the type checker. Named structs and
+// named types annotated with gopenapi:objectSchema are referred to, named types that marshal themselves get the schema
+// of what they write, and other named types are replaced by their underlying type
+func (i *interpretation) setSchemaTypeOfType(schema *models.Schema, t types.Type) error {
+	// An alias may be mapped by its own name, like encoding/json.RawMessage which aliases encoding/json/jsontext.Value
+	if alias, ok := t.(*types.Alias); ok {
+		if mapped, err := i.setMappedSchema(schema, qualifiedName(alias.Obj())); mapped || err != nil {
+			return err
+		}
+	}
+	t = types.Unalias(t)
+	switch t.(type) {
+	case *types.Pointer:
+		return i.setSchemaTypeOfType(schema, t.(*types.Pointer).Elem())
+	case *types.Named:
+		typeName := t.(*types.Named).Obj()
+		if mapped, err := i.setMappedSchema(schema, qualifiedName(typeName)); mapped || err != nil {
+			return err
+		}
+		if typeName.Pkg() != nil && isKnownType(typeName.Pkg().Name()+"."+typeName.Name()) {
+			setSchemaType(schema, typeName.Pkg().Name()+"."+typeName.Name())
+			return nil
+		}
+		declaration, err := i.declarationOfNamed(t.(*types.Named))
+		if err != nil {
+			return err
+		}
+		isAnnotated := declaration != nil && isObjectSchema(declaration.doc)
+		if !isAnnotated && i.setSchemaOfMarshaler(schema, t.(*types.Named)) {
+			return nil
+		}
+		_, isStruct := t.Underlying().(*types.Struct)
+		if !isStruct && !isAnnotated {
+			return i.inlineNamedType(schema, t.(*types.Named), declaration)
+		}
+		if declaration != nil {
+			return i.referToDeclaration(schema, declaration)
+		}
+		schema.Ref = "#/components/schemas/" + i.options.SchemaNaming.name(typeName.Pkg().Name(), typeName.Name())
+		return nil
+	case *types.Basic:
+		basic := t.(*types.Basic)
+		if mapped, err := i.setMappedSchema(schema, basic.Name()); mapped || err != nil {
+			return err
+		}
+		if basic.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) == 0 || basic.Kind() == types.Uintptr {
+			return &unsupportedTypeError{typeName: basic.Name()}
+		}
+		setSchemaType(schema, basic.Name())
+	case *types.Slice:
+		basic, ok := t.(*types.Slice).Elem().(*types.Basic)
+		return setSchemaTypeOfList(schema, ok && basic.Kind() == types.Byte, func(items *models.Schema) error {
+			return i.setSchemaTypeOfType(items, t.(*types.Slice).Elem())
+		})
+	case *types.Array:
+		return setSchemaTypeOfList(schema, false, func(items *models.Schema) error {
+			return i.setSchemaTypeOfType(items, t.(*types.Array).Elem())
+		})
+	case *types.Map:
+		return setSchemaTypeOfMap(schema, func(values *models.Schema) error {
+			return i.setSchemaTypeOfType(values, t.(*types.Map).Elem())
+		})
+	case *types.Struct:
+		setSchemaType(schema, "object")
+	case *types.Interface, *types.TypeParam:
+		// Anything may be assigned to an interface, so its schema is free-form
+	default:
+		return &unsupportedTypeError{typeName: t.String()}
+	}
+	return nil
+}
+
+// setSchemaTypeOfList sets the type of the schema to an array of which setItems sets the type of the items. A byte
+// slice is the exception, as encoding/json writes it as a base64 encoded string
+func setSchemaTypeOfList(schema *models.Schema, isByteSlice bool, setItems func(items *models.Schema) error) error {
+	if isByteSlice {
+		schema.Type = "string"
+		schema.Format = "byte"
+		return nil
+	}
+	setSchemaType(schema, "array")
+	schema.Items = &models.Schema{}
+	return setItems(schema.Items)
+}
+
+// setSchemaTypeOfMap sets the type of the schema to an object of which setValues sets the type of the additional
+// properties
+func setSchemaTypeOfMap(schema *models.Schema, setValues func(values *models.Schema) error) error {
+	setSchemaType(schema, "object")
+	values := &models.Schema{}
+	schema.AdditionalProperties = values
+	return setValues(values)
+}
+
+// inlineNamedType sets the type of the schema to the underlying type of the named type. A type that refers to itself,
+// like type Tree map[string]Tree, can only be described by a reference to its own schema, which is generated when the
+// type is declared in the module and is free-form otherwise
+func (i *interpretation) inlineNamedType(schema *models.Schema, named *types.Named, declaration *typeDeclaration) error {
+	typeName := named.Obj()
+	if i.inlinedTypes[typeName] {
+		if declaration == nil {
+			return nil
+		}
+		schema.Ref = "#/components/schemas/" + i.declarationSchemaName(declaration)
+		return i.includeDeclaration(declaration)
+	}
+	i.markInlined(typeName)
+	defer delete(i.inlinedTypes, typeName)
+	return i.setSchemaTypeOfType(schema, named.Underlying())
+}
+
+// markInlined marks the named type as one of which the underlying type is being resolved
+func (i *interpretation) markInlined(typeName *types.TypeName) {
+	if i.inlinedTypes == nil {
+		i.inlinedTypes = map[*types.TypeName]bool{}
+	}
+	i.inlinedTypes[typeName] = true
+}
+
+// schemaPresenceFromStructField marks the property as required when encoding/json always writes the field, and as
+// nullable when the field is a pointer or a wrapper of a value that may be null. The options of the openapi tag of the
+// field override both
+func schemaPresenceFromStructField(structField *ast.Field, objectSchema *models.Schema, fieldName string, tagOptions []string,
+	openAPIOptions []openAPITagOption) {
+	schema := objectSchema.Properties[fieldName]
+	_, isPointer := structField.Type.(*ast.StarExpr)
+	schema.Nullable = isPointer || isNullableWrapper(structField.Type)
+	if !isPointer && !hasOption(tagOptions, "omitempty") {
+		addRequired(objectSchema, fieldName)
+	}
+	for _, option := range openAPIOptions {
+		switch option.key {
+		case "required":
+			addRequired(objectSchema, fieldName)
+		case "optional":
+			removeRequired(objectSchema, fieldName)
+		case "nullable":
+			schema.Nullable = true
+		case "notnull":
+			schema.Nullable = false
+		}
+	}
+}
+
+// schemaOptionsFromStructField applies the options of the openapi tag of the struct field that describe its property,
+// like readOnly or example=42, and merges the YAML that follows the gopenapi:field annotation of the field over it
+func (i *interpretation) schemaOptionsFromStructField(structField *ast.Field, schema *models.Schema, openAPIOptions []openAPITagOption) error {
+	for _, option := range openAPIOptions {
+		value := option.value
+		switch option.key {
+		case "readOnly":
+			schema.ReadOnly = true
+		case "writeOnly":
+			schema.WriteOnly = true
+		case "deprecated":
+			schema.Deprecated = true
+		case "format":
+			schema.Format = value
+		case "description":
+			schema.Description = value
+		case "example":
+			schema.Example = typedValue(schema, value)
+		case "default":
+			schema.Default = typedValue(schema, value)
+		case "required", "optional", "nullable", "notnull":
+			// The presence of the property is derived from these by schemaPresenceFromStructField
+		default:
+			i.warn("ignoring the unknown option %s of the openapi tag at %s", option.key,
+				i.pkg.fileSet.Position(structField.Pos()))
+		}
+	}
+	for _, a := range annotationsOf(structField.Doc) {
+		if a.keyword == "gopenapi:field" {
+			return i.overrideSchema(schema, a)
+		}
+	}
+	return nil
+}
+
+// isNullableWrapper reports whether the type is a type of gopkg.in/guregu/null, which are written as null when they are
+// not valid
+func isNullableWrapper(typeExpr ast.Expr) bool {
+	selectorExpr, ok := typeExpr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	packageName, ok := selectorExpr.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	return packageName.Name == "null"
+}
+
+func removeRequired(objectSchema *models.Schema, fieldName string) {
+	for i, required := range objectSchema.Required {
+		if required == fieldName {
+			objectSchema.Required = append(objectSchema.Required[:i], objectSchema.Required[i+1:]...)
+			return
+		}
+	}
+}
+
+// embeddedStructOf returns the embedded struct of the field of which the fields are promoted. The embedded struct
+// becomes part of an allOf composition of the schema instead when the interpreter is configured to do so, and it is nil
+// then. The fields of a struct that is not declared in the module are promoted as well when the type checker resolves
+// them. An embedded type that is not a struct is a field like any other, and its embedded struct has no struct type
+func (i *interpretation) embeddedStructOf(embeddedField *ast.Field, newSchema *models.Schema) (*embeddedStruct, error) {
+	typeExpr := embeddedField.Type
+	_, isPointer := typeExpr.(*ast.StarExpr)
+	if isPointer {
+		typeExpr = typeExpr.(*ast.StarExpr).X
+	}
+	declaration, err := i.typeDeclarationOf(typeExpr)
+	if err != nil {
+		return nil, err
+	}
+	if declaration == nil {
+		return i.foreignEmbeddedStructOf(typeExpr, isPointer, newSchema)
+	}
+
+	structType, isStruct := declaration.spec.Type.(*ast.StructType)
+	if !isStruct {
+		return &embeddedStruct{interpretation: i, count: 1}, nil
+	}
+	if i.options.EmbeddedAsAllOf {
+		embeddedSchemaRef := &models.Schema{}
+		newSchema.AllOf = append(newSchema.AllOf, embeddedSchemaRef)
+		return nil, i.referToDeclaration(embeddedSchemaRef, declaration)
+	}
+	d := i.inDeclaration(declaration)
+	if i.pkg.typesInfo != nil {
+		// The fields of an instantiated generic struct have the types of its type arguments instead of its type
+		// parameters
+		if named, ok := types.Unalias(i.typeOf(typeExpr)).(*types.Named); ok && named.TypeArgs().Len() != 0 {
+			d.fieldTypes = fieldTypesOf(structType, named.Underlying().(*types.Struct))
+		}
+	}
+	return &embeddedStruct{interpretation: d, structType: structType, viaPointer: isPointer, count: 1}, nil
+}
+
+// foreignEmbeddedStructOf returns the embedded struct of a field of which the type is not declared in the module. Its
+// fields are promoted like the ones of any other struct, which the type checker tells. Without the type checker they
+// are unknown, and the type may just be declared in a file that does not satisfy the build constraints, so the schema
+// becomes an allOf composition with a reference to the schema of the type instead, and the embedded struct is nil
+func (i *interpretation) foreignEmbeddedStructOf(typeExpr ast.Expr, isPointer bool, newSchema *models.Schema) (*embeddedStruct, error) {
+	if i.pkg.typesInfo == nil {
+		embeddedSchemaRef := &models.Schema{}
+		newSchema.AllOf = append(newSchema.AllOf, embeddedSchemaRef)
+		typeExpr = withoutTypeArguments(typeExpr)
+		return nil, i.setSchemaTypeOfName(embeddedSchemaRef, types.ExprString(typeExpr), typeExpr)
+	}
+	s, isStruct := i.typeOf(typeExpr).Underlying().(*types.Struct)
+	if !isStruct {
+		return &embeddedStruct{interpretation: i, count: 1}, nil
+	}
+	structType, fieldTypes := structTypeOf(s)
+	f := *i
+	f.fieldTypes = fieldTypes
+	return &embeddedStruct{interpretation: &f, structType: structType, viaPointer: isPointer, count: 1}, nil
+}
+
+// structTypeOf returns the syntax of a struct of which only the type is known, along with the types of its fields by
+// their type expressions. The type expressions only name the types of embedded fields, as the types themselves are
+// known
+func structTypeOf(s *types.Struct) (*ast.StructType, map[ast.Expr]types.Type) {
+	structType := &ast.StructType{Fields: &ast.FieldList{}}
+	fieldTypes := map[ast.Expr]types.Type{}
+	for n := 0; n < s.NumFields(); n++ {
+		field := s.Field(n)
+		structField := &ast.Field{Type: ast.NewIdent(field.Name())}
+		if pointer, ok := field.Type().(*types.Pointer); ok && field.Embedded() {
+			structField.Type = &ast.StarExpr{X: structField.Type}
+			fieldTypes[structField.Type.(*ast.StarExpr).X] = pointer.Elem()
+		}
+		if !field.Embedded() {
+			structField.Names = []*ast.Ident{ast.NewIdent(field.Name())}
+		}
+		if s.Tag(n) != "" {
+			structField.Tag = &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s.Tag(n))}
+		}
+		fieldTypes[structField.Type] = field.Type()
+		structType.Fields.List = append(structType.Fields.List, structField)
+	}
+	return structType, fieldTypes
+}
+
+// fieldTypesOf returns the types of the fields of the struct by their type expressions, of which the type checker
+// tells the types, like the types of the fields of an instantiated generic struct
+func fieldTypesOf(structType *ast.StructType, s *types.Struct) map[ast.Expr]types.Type {
+	fieldTypes := map[ast.Expr]types.Type{}
+	n := 0
+	for _, structField := range structType.Fields.List {
+		fieldTypes[structField.Type] = s.Field(n).Type()
+		n += max(len(structField.Names), 1)
+	}
+	return fieldTypes
+}
+
+// composeAllOf turns an object schema that refers to the schemas of embedded structs into an allOf composition of
+// those schemas and an object schema with the own properties of the struct
+func composeAllOf(schema *models.Schema) {
+	if len(schema.AllOf) == 0 {
+		return
+	}
+	if len(schema.Properties) != 0 {
+		schema.AllOf = append(schema.AllOf, &models.Schema{
+			Type:       schema.Type,
+			Properties: schema.Properties,
+			Required:   schema.Required,
+		})
+	}
+	schema.Type = ""
+	schema.Properties = nil
+	schema.Required = nil
+}
+
+// typeDeclarationOf returns the declaration of the named type that the expression refers to, or nil when the type is
+// not declared in the module
+func (i *interpretation) typeDeclarationOf(typeExpr ast.Expr) (*typeDeclaration, error) {
+	if i.pkg.typesInfo != nil {
+		named, ok := types.Unalias(i.typeOf(typeExpr)).(*types.Named)
+		if !ok {
+			return nil, nil
+		}
+		return i.declarationOfNamed(named)
+	}
+	return i.declarationByName(typeExpr)
+}
+
+// declarationByName returns the declaration of the type that the identifier or the qualified identifier refers to, in
+// which packages are referred to by the names under which the file imports them. The instantiation of a generic type
+// refers to the declaration of the generic type. It is nil when the type is not declared in the module
+func (i *interpretation) declarationByName(typeExpr ast.Expr) (*typeDeclaration, error) {
+	typeExpr = withoutTypeArguments(typeExpr)
+	switch typeExpr.(type) {
+	case *ast.Ident:
+		return i.pkg.typeDeclarations[typeExpr.(*ast.Ident).Name], nil
+	case *ast.SelectorExpr:
+		selectorExpr := typeExpr.(*ast.SelectorExpr)
+		packageName, ok := selectorExpr.X.(*ast.Ident)
+		if !ok {
+			return nil, nil
+		}
+		pkg, err := i.pkg.importedPackage(i.resolver, i.file, packageName.Name)
+		if err != nil || pkg == nil {
+			return nil, err
+		}
+		return pkg.typeDeclarations[selectorExpr.Sel.Name], nil
+	}
+	return nil, nil
+}
+
+// declarationOfNamed returns the declaration of a named type resolved by the type checker, or nil when the type is not
+// declared in the module
+func (i *interpretation) declarationOfNamed(named *types.Named) (*typeDeclaration, error) {
+	typeName := named.Obj()
+	if typeName.Pkg() == nil {
+		return nil, nil
+	}
+	pkg := i.pkg
+	if typeName.Pkg().Path() != i.pkg.path {
+		var err error
+		pkg, err = i.resolver.packageOfImport(i.pkg, typeName.Pkg().Path())
+		if err != nil || pkg == nil {
+			return nil, err
+		}
+	}
+	return pkg.typeDeclarations[typeName.Name()], nil
+}
+
+// isObjectSchema reports whether the doc comment of a type declaration annotates it with gopenapi:objectSchema
+func isObjectSchema(doc *ast.CommentGroup) bool {
+	return objectSchemaAnnotation(doc) != nil
+}
+
+// objectSchemaAnnotation returns the gopenapi:objectSchema annotation of the doc comment of a type declaration, or nil
+// when it has none
+func objectSchemaAnnotation(doc *ast.CommentGroup) *annotation {
+	for _, a := range annotationsOf(doc) {
+		if a.keyword == "gopenapi:objectSchema" {
+			return a
+		}
+	}
+	return nil
+}
+
+// isKnownType reports whether the name is the name of a type for which setSchemaType knows the schema
+func isKnownType(typeName string) bool {
+	schema := &models.Schema{}
+	setSchemaType(schema, typeName)
+	return schema.Ref == ""
+}
+
+// describeSchema sets the prose of a doc comment as the description of the schema. A paragraph of the prose that
+// starts with "Deprecated:" marks the schema as deprecated
+func describeSchema(schema *models.Schema, comment string) {
+	schema.Description = prose(comment)
+	for _, paragraph := range strings.Split(schema.Description, "\n\n") {
+		if strings.HasPrefix(paragraph, "Deprecated:") {
+			schema.Deprecated = true
+		}
+	}
+}
+
+func lower(s string) string {
+	a := []rune(s)
+	a[0] = unicode.ToLower(a[0])
+	return string(a)
+}
+
+func setSchemaType(schema *models.Schema, typeName string) {
+	switch typeName {
+	case "bool":
+		schema.Type = "boolean"
+	case "int64", "int", "uint64", "uint", "uint32":
+		schema.Type = "integer"
+		schema.Format = "int64"
+	case "int32", "int16", "int8", "uint16", "uint8", "byte", "rune", "time.Month":
+		schema.Type = "integer"
+		schema.Format = "int32"
+	case "float64", "float":
+		schema.Type = "number"
+		schema.Format = "double"
+	case "float32":
+		schema.Type = "number"
+		schema.Format = "float"
+	case "string":
+		schema.Type = "string"
+	case "array":
+		schema.Type = "array"
+	case "object":
+		schema.Type = "object"
+	case "time.Time", "null.Time":
+		schema.Type = "string"
+		schema.Format = "date-time"
+	case "null.String":
+		schema.Type = "string"
+	case "null.Int":
+		schema.Type = "integer"
+		schema.Format = "int64"
+	case "null.Float":
+		schema.Type = "number"
+		schema.Format = "double"
+	case "null.Bool":
+		schema.Type = "boolean"
+	case "sql.NullString", "sql.NullInt64", "sql.NullInt32", "sql.NullInt16", "sql.NullByte", "sql.NullFloat64",
+		"sql.NullBool", "sql.NullTime":
+		setSQLNullSchema(schema, strings.TrimPrefix(typeName, "sql.Null"))
+	default:
+		schema.Ref = "#/components/schemas/" + lower(typeName)
+	}
+	return
+}
+
+// setSQLNullSchema sets the schema to the one of the Null type of database/sql of which the value field has the name.
+// The type does not marshal itself, so encoding/json writes it as an object of its value and whether it is valid
+func setSQLNullSchema(schema *models.Schema, valueName string) {
+	valueSchema := &models.Schema{}
+	if valueName == "Time" {
+		setSchemaType(valueSchema, "time.Time")
+	} else {
+		setSchemaType(valueSchema, strings.ToLower(valueName))
+	}
+	schema.Type = "object"
+	schema.Properties = map[string]*models.Schema{valueName: valueSchema, "Valid": {Type: "boolean"}}
+	schema.Required = []string{valueName, "Valid"}
+}
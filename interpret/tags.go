@@ -0,0 +1,147 @@
+package interpret
+
+import (
+	"fmt"
+	"github.com/VanMoof/gopenapi/models"
+	"go/ast"
+	"strings"
+)
+
+// NameTag is the key of the struct tags that name the properties of schemas
+type NameTag string
+
+const (
+	// JSONNameTag names properties like encoding/json does
+	JSONNameTag NameTag = "json"
+	// YAMLNameTag names properties like gopkg.in/yaml.v3 does
+	YAMLNameTag NameTag = "yaml"
+)
+
+// ParseNameTag returns the name tag with the name, where an empty name is the json tag
+func ParseNameTag(name string) (NameTag, error) {
+	switch NameTag(name) {
+	case "":
+		return JSONNameTag, nil
+	case JSONNameTag, YAMLNameTag:
+		return NameTag(name), nil
+	}
+	return "", fmt.Errorf("unknown name tag %s", name)
+}
+
+// key returns the key of the struct tags. The zero value of NameTag is the json tag
+func (n NameTag) key() string {
+	if n == "" {
+		return string(JSONNameTag)
+	}
+	return string(n)
+}
+
+// fieldName returns the name of the property of a field, which is the name in its tag when it has one. Without one,
+// encoding/json uses the name of the field and gopkg.in/yaml.v3 uses it in lower case
+func (n NameTag) fieldName(tagName string, goName string) string {
+	if n == YAMLNameTag {
+		if tagName != "" {
+			return tagName
+		}
+		return strings.ToLower(goName)
+	}
+	if isValidTagName(tagName) {
+		return tagName
+	}
+	return goName
+}
+
+// nameTag splits the tag of the struct field that names its property into the name and the options
+func (i *interpretation) nameTag(structField *ast.Field) (string, []string) {
+	tagParts := strings.Split(structTag(structField).Get(i.options.NameTag.key()), ",")
+	return tagParts[0], tagParts[1:]
+}
+
+// openAPITagOption is an option of the openapi tag of a struct field, like readOnly or example=42
+type openAPITagOption struct {
+	key   string
+	value string
+}
+
+// openAPITag splits the openapi tag of the struct field into its options. The options are separated by commas, so a
+// value that contains one is put between single quotes, like description='The order, ascending'
+func openAPITag(structField *ast.Field) []openAPITagOption {
+	var options []openAPITagOption
+	var option strings.Builder
+	quoted := false
+	for _, c := range structTag(structField).Get("openapi") {
+		if c == '\'' {
+			quoted = !quoted
+		}
+		if c == ',' && !quoted {
+			options = appendOpenAPITagOption(options, option.String())
+			option.Reset()
+			continue
+		}
+		option.WriteRune(c)
+	}
+	return appendOpenAPITagOption(options, option.String())
+}
+
+func appendOpenAPITagOption(options []openAPITagOption, option string) []openAPITagOption {
+	if option == "" {
+		return options
+	}
+	key, value, _ := strings.Cut(option, "=")
+	if len(value) > 1 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		value = value[1 : len(value)-1]
+	}
+	return append(options, openAPITagOption{key: key, value: value})
+}
+
+// schemaXMLFromStructField describes how encoding/xml writes the field when its xml tag makes it differ from what the
+// property tells: under another name, in a namespace, as an attribute, or in a parent element like a>b does. The
+// parent element of a slice wraps its items, and without one the items are the elements that the name applies to
+func schemaXMLFromStructField(structField *ast.Field, schema *models.Schema) {
+	tag, ok := structTag(structField).Lookup("xml")
+	if !ok || tag == "-" {
+		return
+	}
+	tagParts := strings.Split(tag, ",")
+	xml := xmlOfName(tagParts[0])
+	xml.Attribute = hasOption(tagParts[1:], "attr")
+	elements := strings.Split(xml.Name, ">")
+	if len(elements) > 1 && schema.Type == "array" && schema.Items != nil {
+		xml.Name = elements[len(elements)-2]
+		xml.Wrapped = true
+		schema.Items.XML = &models.XML{Name: elements[len(elements)-1]}
+	} else if schema.Type == "array" && schema.Items != nil {
+		itemsXML := &models.XML{Name: elements[len(elements)-1], Namespace: xml.Namespace}
+		if itemsXML.Name != "" || itemsXML.Namespace != "" {
+			schema.Items.XML = itemsXML
+		}
+		xml.Name = ""
+		xml.Namespace = ""
+	} else {
+		xml.Name = elements[len(elements)-1]
+	}
+	if xml.Name != "" || xml.Namespace != "" || xml.Attribute || xml.Wrapped {
+		schema.XML = xml
+	}
+}
+
+// schemaXMLFromXMLName describes the element of a struct with the xml tag of its XMLName field, like encoding/xml
+func schemaXMLFromXMLName(structField *ast.Field, schema *models.Schema) {
+	tag := structTag(structField).Get("xml")
+	if tag == "" || tag == "-" {
+		return
+	}
+	xml := xmlOfName(strings.Split(tag, ",")[0])
+	if xml.Name != "" || xml.Namespace != "" {
+		schema.XML = xml
+	}
+}
+
+// xmlOfName returns the XML element of the name in an xml tag, which is preceded by its namespace and a space when it
+// has one
+func xmlOfName(name string) *models.XML {
+	if namespace, localName, found := strings.Cut(name, " "); found {
+		return &models.XML{Name: localName, Namespace: namespace}
+	}
+	return &models.XML{Name: name}
+}
@@ -0,0 +1,243 @@
+package interpret
+
+import (
+	"github.com/VanMoof/gopenapi/models"
+	"go/ast"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// validationTagKeys are the struct tag keys of go-playground/validator and of gin, which both use the validator syntax
+var validationTagKeys = []string{"validate", "binding"}
+
+// validationFormats maps the validator tags that check a well known string format to that format
+var validationFormats = map[string]string{
+	"email":            "email",
+	"url":              "uri",
+	"uri":              "uri",
+	"http_url":         "uri",
+	"uuid":             "uuid",
+	"uuid3":            "uuid",
+	"uuid4":            "uuid",
+	"uuid5":            "uuid",
+	"hostname":         "hostname",
+	"hostname_rfc1123": "hostname",
+	"fqdn":             "hostname",
+	"ipv4":             "ipv4",
+	"ip4_addr":         "ipv4",
+	"ipv6":             "ipv6",
+	"ip6_addr":         "ipv6",
+	"base64":           "byte",
+}
+
+// validationPatterns maps the validator tags that check the characters of a string to an equivalent pattern
+var validationPatterns = map[string]string{
+	"alpha":       "^[a-zA-Z]+$",
+	"alphanum":    "^[a-zA-Z0-9]+$",
+	"numeric":     "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":      "^[0-9]+$",
+	"hexadecimal": "^(0[xX])?[0-9a-fA-F]+$",
+	"e164":        "^\\+[1-9]?[0-9]{7,14}$",
+}
+
+// validationDateTimeFormats maps the layouts of the validator "datetime" tag to the matching format
+var validationDateTimeFormats = map[string]string{
+	"2006-01-02T15:04:05Z07:00": "date-time",
+	"2006-01-02":                "date",
+}
+
+// schemaValidationFromStructField translates the validation tags of the struct field into the constraints of the
+// property schema. A required field is added to the required properties of the object schema
+func schemaValidationFromStructField(structField *ast.Field, objectSchema *models.Schema, fieldName string) {
+	tag := structTag(structField)
+	for _, tagKey := range validationTagKeys {
+		rules := tag.Get(tagKey)
+		if rules == "" {
+			continue
+		}
+		schema := objectSchema.Properties[fieldName]
+		skippingKeys := false
+		for _, rule := range strings.Split(rules, ",") {
+			rule = strings.ReplaceAll(rule, "0x2C", ",")
+			name, parameter := rule, ""
+			if i := strings.Index(rule, "="); i != -1 {
+				name, parameter = rule[:i], rule[i+1:]
+			}
+			switch {
+			case name == "keys":
+				skippingKeys = true
+			case name == "endkeys":
+				skippingKeys = false
+			case skippingKeys || strings.Contains(rule, "|"):
+			case name == "dive":
+				schema = elementSchema(schema)
+			case name == "required" && schema == objectSchema.Properties[fieldName]:
+				addRequired(objectSchema, fieldName)
+			default:
+				schemaValidationFromRule(schema, name, parameter)
+			}
+		}
+	}
+}
+
+// elementSchema returns the schema of the elements of an array or map schema
+func elementSchema(schema *models.Schema) *models.Schema {
+	if schema == nil {
+		return nil
+	}
+	if schema.Items != nil {
+		return schema.Items
+	}
+	if additionalProperties, ok := schema.AdditionalProperties.(*models.Schema); ok {
+		return additionalProperties
+	}
+	return nil
+}
+
+func addRequired(objectSchema *models.Schema, fieldName string) {
+	for _, required := range objectSchema.Required {
+		if required == fieldName {
+			return
+		}
+	}
+	objectSchema.Required = append(objectSchema.Required, fieldName)
+}
+
+// schemaValidationFromRule applies a single validator rule to the schema, depending on the type of the schema. Rules
+// that have no equivalent in the schema are ignored
+func schemaValidationFromRule(schema *models.Schema, name string, parameter string) {
+	if schema == nil || schema.Type == "" {
+		return
+	}
+	switch name {
+	case "min", "gte":
+		setLowerBound(schema, parameter, false)
+	case "gt":
+		setLowerBound(schema, parameter, true)
+	case "max", "lte":
+		setUpperBound(schema, parameter, false)
+	case "lt":
+		setUpperBound(schema, parameter, true)
+	case "len":
+		if schema.Type == "string" || schema.Type == "array" {
+			setLowerBound(schema, parameter, false)
+			setUpperBound(schema, parameter, false)
+		}
+	case "oneof":
+		schema.Enum = nil
+		for _, value := range splitOneOf(parameter) {
+			schema.Enum = append(schema.Enum, typedValue(schema, value))
+		}
+	case "startswith":
+		setPattern(schema, "^"+regexp.QuoteMeta(parameter))
+	case "endswith":
+		setPattern(schema, regexp.QuoteMeta(parameter)+"$")
+	case "contains":
+		setPattern(schema, regexp.QuoteMeta(parameter))
+	case "datetime":
+		if format, ok := validationDateTimeFormats[parameter]; ok && schema.Type == "string" {
+			schema.Format = format
+		}
+	default:
+		if format, ok := validationFormats[name]; ok && schema.Type == "string" {
+			schema.Format = format
+		}
+		if pattern, ok := validationPatterns[name]; ok {
+			setPattern(schema, pattern)
+		}
+	}
+}
+
+func setLowerBound(schema *models.Schema, parameter string, exclusive bool) {
+	switch schema.Type {
+	case "integer", "number":
+		if value, err := strconv.ParseFloat(parameter, 64); err == nil {
+			schema.Minimum = &value
+			schema.ExclusiveMinimum = exclusive
+		}
+	case "string":
+		if value, ok := parseLength(parameter, exclusive); ok {
+			schema.MinLength = &value
+		}
+	case "array":
+		if value, ok := parseLength(parameter, exclusive); ok {
+			schema.MinItems = &value
+		}
+	}
+}
+
+func setUpperBound(schema *models.Schema, parameter string, exclusive bool) {
+	switch schema.Type {
+	case "integer", "number":
+		if value, err := strconv.ParseFloat(parameter, 64); err == nil {
+			schema.Maximum = &value
+			schema.ExclusiveMaximum = exclusive
+		}
+	case "string":
+		if value, ok := parseLength(parameter, false); ok {
+			if exclusive && value > 0 {
+				value--
+			}
+			schema.MaxLength = &value
+		}
+	case "array":
+		if value, ok := parseLength(parameter, false); ok {
+			if exclusive && value > 0 {
+				value--
+			}
+			schema.MaxItems = &value
+		}
+	}
+}
+
+// parseLength parses the parameter of a length rule. An exclusive lower bound is turned into the inclusive one
+func parseLength(parameter string, exclusive bool) (uint64, bool) {
+	value, err := strconv.ParseUint(parameter, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if exclusive {
+		value++
+	}
+	return value, true
+}
+
+// setPattern sets the pattern of a string schema. Only the first pattern is kept, since a schema can have just one
+func setPattern(schema *models.Schema, pattern string) {
+	if schema.Type == "string" && schema.Pattern == "" {
+		schema.Pattern = pattern
+	}
+}
+
+var oneOfValuePattern = regexp.MustCompile(`'[^']*'|\S+`)
+
+// splitOneOf splits the parameter of a oneof rule into its values, which are separated by spaces and may be quoted
+// with single quotes
+func splitOneOf(parameter string) []string {
+	var values []string
+	for _, match := range oneOfValuePattern.FindAllString(parameter, -1) {
+		values = append(values, strings.Trim(match, "'"))
+	}
+	return values
+}
+
+// typedValue converts the value of a rule or of an option of the openapi tag to the type of the schema, and keeps it a
+// string when it is not of that type
+func typedValue(schema *models.Schema, value string) interface{} {
+	switch schema.Type {
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
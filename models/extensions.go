@@ -0,0 +1,258 @@
+package models
+
+import (
+	"encoding/json"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Extensions holds the specification extensions of an element, which are the properties with a name that begins with
+// "x-". They are written inline with the other properties of the element
+type Extensions map[string]interface{}
+
+// specificationExtensions drops the properties that are not specification extensions. When decoding YAML, the inline
+// extensions receive every property that is unknown to the element
+func (e Extensions) specificationExtensions() Extensions {
+	for name := range e {
+		if !strings.HasPrefix(name, "x-") {
+			delete(e, name)
+		}
+	}
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// plainTypes holds the struct types that plain converts the elements to, by the types of the elements
+var plainTypes sync.Map
+
+// plain converts the pointer to an element to a pointer to a struct that has the same fields but none of the methods
+// of the element, so that encoding/json and gopkg.in/yaml.v3 encode and decode the fields of the element without
+// calling the methods that do so with its extensions. It returns the extensions of the element as well
+func plain(elementPointer interface{}) (interface{}, *Extensions) {
+	element := reflect.ValueOf(elementPointer).Elem()
+	plainType, ok := plainTypes.Load(element.Type())
+	if !ok {
+		fields := make([]reflect.StructField, element.NumField())
+		for f := range fields {
+			fields[f] = element.Type().Field(f)
+		}
+		plainType, _ = plainTypes.LoadOrStore(element.Type(), reflect.StructOf(fields))
+	}
+	plainPointer := element.Addr().Convert(reflect.PointerTo(plainType.(reflect.Type))).Interface()
+	return plainPointer, element.FieldByName("Extensions").Addr().Interface().(*Extensions)
+}
+
+// decodeYAML decodes the YAML of an element, of which the inline extensions only keep the specification extensions
+func decodeYAML(value *yaml.Node, elementPointer interface{}) error {
+	plainPointer, extensions := plain(elementPointer)
+	err := value.Decode(plainPointer)
+	*extensions = extensions.specificationExtensions()
+	return err
+}
+
+// encodeJSON encodes the JSON of an element with its extensions inline
+func encodeJSON(elementPointer interface{}) ([]byte, error) {
+	plainPointer, extensions := plain(elementPointer)
+	encoded, err := json.Marshal(plainPointer)
+	if err != nil || len(*extensions) == 0 {
+		return encoded, err
+	}
+	encodedExtensions, err := json.Marshal(map[string]interface{}(*extensions))
+	if err != nil {
+		return nil, err
+	}
+	if string(encoded) == "{}" {
+		return encodedExtensions, nil
+	}
+	encoded = append(encoded[:len(encoded)-1], ',')
+	return append(encoded, encodedExtensions[1:]...), nil
+}
+
+// decodeJSON decodes the JSON of an element and collects the specification extensions in it
+func decodeJSON(data []byte, elementPointer interface{}) error {
+	plainPointer, extensions := plain(elementPointer)
+	if err := json.Unmarshal(data, plainPointer); err != nil {
+		return err
+	}
+	properties := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return err
+	}
+	*extensions = nil
+	for name, encodedValue := range properties {
+		if !strings.HasPrefix(name, "x-") {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(encodedValue, &value); err != nil {
+			return err
+		}
+		if *extensions == nil {
+			*extensions = Extensions{}
+		}
+		(*extensions)[name] = value
+	}
+	return nil
+}
+
+// The elements of the specification encode and decode their extensions inline with their other properties
+
+func (r *Root) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, r) }
+func (r Root) MarshalJSON() ([]byte, error)      { return encodeJSON(&r) }
+func (r *Root) UnmarshalJSON(data []byte) error  { return decodeJSON(data, r) }
+
+func (i *Info) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, i) }
+func (i Info) MarshalJSON() ([]byte, error)      { return encodeJSON(&i) }
+func (i *Info) UnmarshalJSON(data []byte) error  { return decodeJSON(data, i) }
+
+func (c *Contact) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, c) }
+func (c Contact) MarshalJSON() ([]byte, error)      { return encodeJSON(&c) }
+func (c *Contact) UnmarshalJSON(data []byte) error  { return decodeJSON(data, c) }
+
+func (l *License) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, l) }
+func (l License) MarshalJSON() ([]byte, error)      { return encodeJSON(&l) }
+func (l *License) UnmarshalJSON(data []byte) error  { return decodeJSON(data, l) }
+
+func (s *ServerVariable) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, s) }
+func (s ServerVariable) MarshalJSON() ([]byte, error)      { return encodeJSON(&s) }
+func (s *ServerVariable) UnmarshalJSON(data []byte) error  { return decodeJSON(data, s) }
+
+func (s *Server) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, s) }
+func (s Server) MarshalJSON() ([]byte, error)      { return encodeJSON(&s) }
+func (s *Server) UnmarshalJSON(data []byte) error  { return decodeJSON(data, s) }
+
+func (p *PathItem) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, p) }
+func (p PathItem) MarshalJSON() ([]byte, error)      { return encodeJSON(&p) }
+func (p *PathItem) UnmarshalJSON(data []byte) error  { return decodeJSON(data, p) }
+
+func (o *Operation) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, o) }
+func (o Operation) MarshalJSON() ([]byte, error)      { return encodeJSON(&o) }
+func (o *Operation) UnmarshalJSON(data []byte) error  { return decodeJSON(data, o) }
+
+func (p *Parameter) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, p) }
+func (p Parameter) MarshalJSON() ([]byte, error)      { return encodeJSON(&p) }
+func (p *Parameter) UnmarshalJSON(data []byte) error  { return decodeJSON(data, p) }
+
+func (r *RequestBody) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, r) }
+func (r RequestBody) MarshalJSON() ([]byte, error)      { return encodeJSON(&r) }
+func (r *RequestBody) UnmarshalJSON(data []byte) error  { return decodeJSON(data, r) }
+
+func (m *MediaType) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, m) }
+func (m MediaType) MarshalJSON() ([]byte, error)      { return encodeJSON(&m) }
+func (m *MediaType) UnmarshalJSON(data []byte) error  { return decodeJSON(data, m) }
+
+func (h *Header) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, h) }
+func (h Header) MarshalJSON() ([]byte, error)      { return encodeJSON(&h) }
+func (h *Header) UnmarshalJSON(data []byte) error  { return decodeJSON(data, h) }
+
+func (e *Encoding) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, e) }
+func (e Encoding) MarshalJSON() ([]byte, error)      { return encodeJSON(&e) }
+func (e *Encoding) UnmarshalJSON(data []byte) error  { return decodeJSON(data, e) }
+
+func (r *Response) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, r) }
+func (r Response) MarshalJSON() ([]byte, error)      { return encodeJSON(&r) }
+func (r *Response) UnmarshalJSON(data []byte) error  { return decodeJSON(data, r) }
+
+func (l *Link) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, l) }
+func (l Link) MarshalJSON() ([]byte, error)      { return encodeJSON(&l) }
+func (l *Link) UnmarshalJSON(data []byte) error  { return decodeJSON(data, l) }
+
+func (e *Example) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, e) }
+func (e Example) MarshalJSON() ([]byte, error)      { return encodeJSON(&e) }
+func (e *Example) UnmarshalJSON(data []byte) error  { return decodeJSON(data, e) }
+
+func (c *Components) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, c) }
+func (c Components) MarshalJSON() ([]byte, error)      { return encodeJSON(&c) }
+func (c *Components) UnmarshalJSON(data []byte) error  { return decodeJSON(data, c) }
+
+func (t *Tag) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, t) }
+func (t Tag) MarshalJSON() ([]byte, error)      { return encodeJSON(&t) }
+func (t *Tag) UnmarshalJSON(data []byte) error  { return decodeJSON(data, t) }
+
+func (e *ExternalDocumentation) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, e) }
+func (e ExternalDocumentation) MarshalJSON() ([]byte, error)      { return encodeJSON(&e) }
+func (e *ExternalDocumentation) UnmarshalJSON(data []byte) error  { return decodeJSON(data, e) }
+
+func (x *XML) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, x) }
+func (x XML) MarshalJSON() ([]byte, error)      { return encodeJSON(&x) }
+func (x *XML) UnmarshalJSON(data []byte) error  { return decodeJSON(data, x) }
+
+func (d *Discriminator) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, d) }
+func (d Discriminator) MarshalJSON() ([]byte, error)      { return encodeJSON(&d) }
+func (d *Discriminator) UnmarshalJSON(data []byte) error  { return decodeJSON(data, d) }
+
+func (s *SecurityScheme) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, s) }
+func (s SecurityScheme) MarshalJSON() ([]byte, error)      { return encodeJSON(&s) }
+func (s *SecurityScheme) UnmarshalJSON(data []byte) error  { return decodeJSON(data, s) }
+
+func (o *OAuthFlows) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, o) }
+func (o OAuthFlows) MarshalJSON() ([]byte, error)      { return encodeJSON(&o) }
+func (o *OAuthFlows) UnmarshalJSON(data []byte) error  { return decodeJSON(data, o) }
+
+func (o *OathFlowObject) UnmarshalYAML(n *yaml.Node) error { return decodeYAML(n, o) }
+func (o OathFlowObject) MarshalJSON() ([]byte, error)      { return encodeJSON(&o) }
+func (o *OathFlowObject) UnmarshalJSON(data []byte) error  { return decodeJSON(data, o) }
+
+// UnmarshalYAML decodes the additional properties either as a boolean or as a *Schema
+func (s *Schema) UnmarshalYAML(value *yaml.Node) error {
+	if err := decodeYAML(value, s); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if value.Content[i].Value != "additionalProperties" {
+			continue
+		}
+		additionalPropertiesNode := value.Content[i+1]
+		if additionalPropertiesNode.Kind == yaml.ScalarNode {
+			var allowed bool
+			if err := additionalPropertiesNode.Decode(&allowed); err != nil {
+				return err
+			}
+			s.AdditionalProperties = allowed
+			return nil
+		}
+		additionalProperties := &Schema{}
+		if err := additionalPropertiesNode.Decode(additionalProperties); err != nil {
+			return err
+		}
+		s.AdditionalProperties = additionalProperties
+	}
+	return nil
+}
+
+func (s Schema) MarshalJSON() ([]byte, error) { return encodeJSON(&s) }
+
+// UnmarshalJSON decodes the additional properties either as a boolean or as a *Schema
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	if err := decodeJSON(data, s); err != nil {
+		return err
+	}
+	decoded := struct {
+		AdditionalProperties json.RawMessage `json:"additionalProperties,omitempty"`
+	}{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	s.AdditionalProperties = nil
+	if len(decoded.AdditionalProperties) == 0 {
+		return nil
+	}
+	if strings.HasPrefix(string(decoded.AdditionalProperties), "{") {
+		additionalProperties := &Schema{}
+		if err := json.Unmarshal(decoded.AdditionalProperties, additionalProperties); err != nil {
+			return err
+		}
+		s.AdditionalProperties = additionalProperties
+		return nil
+	}
+	var allowed bool
+	if err := json.Unmarshal(decoded.AdditionalProperties, &allowed); err != nil {
+		return err
+	}
+	s.AdditionalProperties = allowed
+	return nil
+}
@@ -1,6 +1,10 @@
 package models
 
-import "gopkg.in/yaml.v3"
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
 
 type Root struct {
 	OpenAPI               string                 `json:"openapi" yaml:"openapi"`
@@ -8,9 +12,11 @@ type Root struct {
 	Servers               []*Server              `json:"servers,omitempty" yaml:"servers,omitempty"`
 	Paths                 PathItems              `json:"paths" yaml:"paths"`
 	Components            *Components            `json:"components,omitempty" yaml:"components,omitempty"`
-	Security              []*SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`
-	Tags                  []*Tag                 `json:"tag,omitempty" yaml:"tag,omitempty"`
+	Security              []SecurityRequirement  `json:"security,omitempty" yaml:"security,omitempty"`
+	Tags                  []*Tag                 `json:"tags,omitempty" yaml:"tags,omitempty"`
 	ExternalDocumentation *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type PathItems map[string]*PathItem
@@ -35,6 +41,39 @@ func (n *PathItems) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// AddOperation merges the operation into the item of the path under the given HTTP method. It fails when the path
+// already has an operation for that method
+func (n *PathItems) AddOperation(path string, method string, operation *Operation) error {
+	pathItem := &PathItem{}
+	operationPointer := pathItem.operationPointer(method)
+	if operationPointer == nil {
+		return fmt.Errorf("unknown method %s", method)
+	}
+	*operationPointer = operation
+	return n.AddPathItem(path, pathItem)
+}
+
+// AddPathItem merges the item into the item of the path. It fails when the path already has an operation for one of
+// the HTTP methods of the item
+func (n *PathItems) AddPathItem(path string, pathItem *PathItem) error {
+	if *n == nil {
+		*n = map[string]*PathItem{}
+	}
+
+	existingPathItem, ok := (*n)[path]
+	if !ok {
+		(*n)[path] = pathItem
+		return nil
+	}
+	for _, method := range httpMethods {
+		if *pathItem.operationPointer(method) != nil && *existingPathItem.operationPointer(method) != nil {
+			return fmt.Errorf("operation %s %s is already defined", strings.ToUpper(method), path)
+		}
+	}
+	existingPathItem.merge(pathItem)
+	return nil
+}
+
 type Info struct {
 	Title          string   `json:"title" yaml:"title"`
 	Description    string   `json:"description,omitempty" yaml:"description,omitempty"`
@@ -42,29 +81,39 @@ type Info struct {
 	Contact        *Contact `json:"contact,omitempty" yaml:"contact,omitempty"`
 	License        *License `json:"license,omitempty" yaml:"license,omitempty"`
 	Version        string   `json:"version" yaml:"version"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Contact struct {
 	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
 	URL   string `json:"url,omitempty" yaml:"url,omitempty"`
 	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type License struct {
 	Name string `json:"name" yaml:"name"`
 	URL  string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type ServerVariable struct {
 	Enum        []string `json:"enum,omitempty" yaml:"enum,omitempty"`
 	Default     string   `json:"default" yaml:"default"`
 	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Server struct {
 	URL         string                     `json:"url" yaml:"url"`
 	Description string                     `json:"description,omitempty" yaml:"description,omitempty"`
 	Variables   map[string]*ServerVariable `json:"variables,omitempty" yaml:"variables,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type PathItem struct {
@@ -81,6 +130,35 @@ type PathItem struct {
 	Trace       *Operation   `json:"trace,omitempty" yaml:"trace,omitempty"`
 	Servers     []*Server    `json:"servers,omitempty" yaml:"servers,omitempty"`
 	Parameters  []*Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
+}
+
+// httpMethods are the HTTP methods of which a path item has an operation
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// operationPointer returns a pointer to the field of the operation of the given HTTP method, or nil when the method is
+// unknown
+func (p *PathItem) operationPointer(method string) **Operation {
+	switch strings.ToLower(method) {
+	case "get":
+		return &p.Get
+	case "put":
+		return &p.Put
+	case "post":
+		return &p.Post
+	case "delete":
+		return &p.Delete
+	case "options":
+		return &p.Options
+	case "head":
+		return &p.Head
+	case "patch":
+		return &p.Patch
+	case "trace":
+		return &p.Trace
+	}
+	return nil
 }
 
 func (p *PathItem) merge(other *PathItem) {
@@ -120,6 +198,12 @@ func (p *PathItem) merge(other *PathItem) {
 	if len(other.Servers) > 0 {
 		p.Servers = append(p.Servers, other.Servers...)
 	}
+	for name, value := range other.Extensions {
+		if p.Extensions == nil {
+			p.Extensions = Extensions{}
+		}
+		p.Extensions[name] = value
+	}
 	if len(other.Parameters) > 0 {
 		existingParams := map[string]interface{}{}
 		for _, param := range p.Parameters {
@@ -134,19 +218,23 @@ func (p *PathItem) merge(other *PathItem) {
 	}
 }
 
+// Operation keeps its Security as a pointer, so that an explicitly empty list, which removes the root level security
+// from the operation, is kept apart from an absent one
 type Operation struct {
 	Tags                  []string               `json:"tags,omitempty" yaml:"tags,omitempty"`
 	Summary               string                 `json:"summary,omitempty" yaml:"summary,omitempty"`
 	Description           string                 `json:"description,omitempty" yaml:"description,omitempty"`
 	ExternalDocumentation *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
 	OperationID           string                 `json:"operationId,omitempty" yaml:"operationId,omitempty"`
-	Parameters            []*Parameter           `json:"parameter,omitempty" yaml:"parameter,omitempty"`
+	Parameters            []*Parameter           `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 	RequestBody           *RequestBody           `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
 	Responses             map[string]*Response   `json:"responses" yaml:"responses"`
 	Callbacks             map[string]*Callback   `json:"callbacks,omitempty" yaml:"callbacks,omitempty"`
 	Deprecated            bool                   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
-	Security              []*SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`
+	Security              *[]SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`
 	Servers               []*Server              `json:"servers,omitempty" yaml:"servers,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Parameter struct {
@@ -163,12 +251,16 @@ type Parameter struct {
 	Example         interface{}           `json:"example,omitempty" yaml:"example,omitempty"`
 	Examples        map[string]*Example   `json:"examples,omitempty" yaml:"examples,omitempty"`
 	Content         map[string]*MediaType `json:"content" yaml:"content"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type RequestBody struct {
 	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
 	Content     map[string]*MediaType `json:"content" yaml:"content"`
 	Required    bool                  `json:"required,omitempty" yaml:"required,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type MediaType struct {
@@ -176,6 +268,8 @@ type MediaType struct {
 	Example  interface{}          `json:"example,omitempty" yaml:"example,omitempty"`
 	Examples map[string]*Example  `json:"examples,omitempty" yaml:"examples,omitempty"`
 	Encoding map[string]*Encoding `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Header struct {
@@ -190,6 +284,8 @@ type Header struct {
 	Example         interface{}           `json:"example,omitempty" yaml:"example,omitempty"`
 	Examples        map[string]*Example   `json:"examples,omitempty" yaml:"examples,omitempty"`
 	Content         map[string]*MediaType `json:"content" yaml:"content"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Encoding struct {
@@ -198,6 +294,8 @@ type Encoding struct {
 	Style         string             `json:"style,omitempty" yaml:"style,omitempty"`
 	Explode       bool               `json:"explode,omitempty" yaml:"explode,omitempty"`
 	AllowReserved bool               `json:"allowReserved,omitempty" yaml:"allowReserved,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Response struct {
@@ -206,6 +304,8 @@ type Response struct {
 	Headers     map[string]*Header    `json:"headers,omitempty" yaml:"headers,omitempty"`
 	Content     map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"`
 	Links       map[string]*Link      `json:"links,omitempty" yaml:"links,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Link struct {
@@ -215,6 +315,8 @@ type Link struct {
 	RequestBody  interface{}            `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
 	Description  string                 `json:"description" yaml:"description"`
 	Server       *Server                `json:"server,omitempty" yaml:"server,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Example struct {
@@ -222,6 +324,8 @@ type Example struct {
 	Description   string      `json:"description,omitempty" yaml:"description,omitempty"`
 	Value         interface{} `json:"value,omitempty" yaml:"value,omitempty"`
 	ExternalValue string      `json:"externalValue,omitempty" yaml:"externalValue,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Components struct {
@@ -234,21 +338,26 @@ type Components struct {
 	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
 	Links           map[string]*Link           `json:"links,omitempty" yaml:"links,omitempty"`
 	Callbacks       map[string]*Callback       `json:"callbacks,omitempty" yaml:"callbacks,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Tag struct {
 	Name                  string                 `json:"name" yaml:"name"`
 	Description           string                 `json:"description,omitempty" yaml:"description,omitempty"`
 	ExternalDocumentation *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
-}
 
-type SecurityRequirement struct {
-	Name []string `json:"name,omitempty" yaml:"name,omitempty"`
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
+// SecurityRequirement maps the names of the required security schemes to the scopes that are required of them
+type SecurityRequirement map[string][]string
+
 type ExternalDocumentation struct {
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 	URL         string `json:"url" yaml:"url"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Callback map[string]*PathItem
@@ -264,17 +373,40 @@ type Schema struct {
 	Example               interface{}            `json:"example,omitempty" yaml:"example,omitempty"`
 	Deprecated            bool                   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
 
+	Title                string             `json:"title,omitempty" yaml:"title,omitempty"`
 	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
 	AllOf                []*Schema          `json:"allOf,omitempty" yaml:"allOf,omitempty"`
 	OneOf                []*Schema          `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
 	AnyOf                []*Schema          `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
-	Not                  []*Schema          `json:"not,omitempty" yaml:"not,omitempty"`
+	Not                  *Schema            `json:"not,omitempty" yaml:"not,omitempty"`
 	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
 	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
 	AdditionalProperties interface{}        `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
 	Description          string             `json:"description,omitempty" yaml:"description,omitempty"`
 	Default              interface{}        `json:"default,omitempty" yaml:"default,omitempty"`
 	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+
+	Required         []string      `json:"required,omitempty" yaml:"required,omitempty"`
+	Enum             []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+	MultipleOf       *float64      `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	ExclusiveMinimum bool          `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMaximum bool          `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	MinLength        *uint64       `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength        *uint64       `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	Pattern          string        `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	MinItems         *uint64       `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems         *uint64       `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	UniqueItems      bool          `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
+	MinProperties    *uint64       `json:"minProperties,omitempty" yaml:"minProperties,omitempty"`
+	MaxProperties    *uint64       `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty"`
+
+	// GoType names the Go type of which the schema should be generated. The interpreter replaces it with a reference to
+	// the generated schema
+	GoType string `json:"-" yaml:"goType,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type XML struct {
@@ -283,11 +415,15 @@ type XML struct {
 	Prefix    string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
 	Attribute bool   `json:"attribute,omitempty" yaml:"attribute,omitempty"`
 	Wrapped   bool   `json:"wrapped,omitempty" yaml:"wrapped,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type Discriminator struct {
 	PropertyName string            `json:"propertyName" yaml:"propertyName"`
 	Mapping      map[string]string `json:"mapping,omitempty" yaml:"mapping,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type SecurityScheme struct {
@@ -299,6 +435,8 @@ type SecurityScheme struct {
 	BearerFormat     string      `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
 	Flows            *OAuthFlows `json:"flows,omitempty" yaml:"flows,omitempty"`
 	OpenIdConnectUrl string      `json:"openIdConnectUrl,omitempty" yaml:"openIdConnectUrl,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type OAuthFlows struct {
@@ -306,11 +444,15 @@ type OAuthFlows struct {
 	Password          *OathFlowObject `json:"password,omitempty" yaml:"password,omitempty"`
 	ClientCredentials *OathFlowObject `json:"clientCredentials,omitempty" yaml:"clientCredentials,omitempty"`
 	AuthorizationCode *OathFlowObject `json:"authorizationCode,omitempty" yaml:"authorizationCode,omitempty"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
 
 type OathFlowObject struct {
-	AuthorizationURL string            `json:"authorizationUrl" yaml:"authorizationUrl"`
-	TokenURL         string            `json:"tokenUrl" yaml:"tokenUrl"`
+	AuthorizationURL string            `json:"authorizationUrl,omitempty" yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
 	RefreshURL       string            `json:"refreshUrl,omitempty" yaml:"refreshUrl,omitempty"`
 	Scopes           map[string]string `json:"scopes" yaml:"scopes"`
+
+	Extensions Extensions `json:"-" yaml:",inline"`
 }
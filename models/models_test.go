@@ -116,6 +116,141 @@ func TestComponents_JSON(t *testing.T) {
 	validateComponents(a, c2)
 }
 
+func TestSecurityRequirements_YAML(t *testing.T) {
+	o := operationWithSecurity()
+
+	encoded, err := yaml.Marshal(o)
+
+	a := assert.New(t)
+	a.NoError(err)
+
+	o2 := &models.Operation{}
+	a.NoError(yaml.Unmarshal(encoded, o2))
+
+	validateOperationWithSecurity(a, o2)
+}
+
+func TestSecurityRequirements_JSON(t *testing.T) {
+	o := operationWithSecurity()
+
+	encoded, err := json.Marshal(o)
+
+	a := assert.New(t)
+	a.NoError(err)
+
+	o2 := &models.Operation{}
+	a.NoError(json.Unmarshal(encoded, o2))
+
+	validateOperationWithSecurity(a, o2)
+}
+
+func TestSecurityRequirements_EmptyOverride(t *testing.T) {
+	a := assert.New(t)
+
+	o := &models.Operation{}
+	a.NoError(yaml.Unmarshal([]byte("security: []\n"), o))
+	a.NotNil(o.Security)
+	a.Empty(*o.Security)
+
+	encoded, err := json.Marshal(o)
+	a.NoError(err)
+	a.Contains(string(encoded), `"security":[]`)
+
+	o2 := &models.Operation{}
+	a.NoError(yaml.Unmarshal([]byte("summary: summary\n"), o2))
+	a.Nil(o2.Security)
+}
+
+func TestSchema_YAML(t *testing.T) {
+	s := schema()
+
+	encoded, err := yaml.Marshal(s)
+
+	a := assert.New(t)
+	a.NoError(err)
+
+	s2 := &models.Schema{}
+	a.NoError(yaml.Unmarshal(encoded, s2))
+
+	validateSchema(a, s2)
+}
+
+func TestSchema_JSON(t *testing.T) {
+	s := schema()
+
+	encoded, err := json.Marshal(s)
+
+	a := assert.New(t)
+	a.NoError(err)
+
+	s2 := &models.Schema{}
+	a.NoError(json.Unmarshal(encoded, s2))
+
+	validateSchema(a, s2)
+}
+
+func TestSchema_AdditionalPropertiesAllowed(t *testing.T) {
+	a := assert.New(t)
+
+	s := &models.Schema{}
+	a.NoError(yaml.Unmarshal([]byte("type: object\nadditionalProperties: false\n"), s))
+	a.Equal(false, s.AdditionalProperties)
+
+	s2 := &models.Schema{}
+	a.NoError(json.Unmarshal([]byte(`{"type":"object","additionalProperties":true}`), s2))
+	a.Equal(true, s2.AdditionalProperties)
+
+	s3 := &models.Schema{}
+	a.NoError(json.Unmarshal([]byte(`{"type":"object"}`), s3))
+	a.Nil(s3.AdditionalProperties)
+}
+
+func TestExtensions_YAML(t *testing.T) {
+	r := rootWithExtensions()
+
+	encoded, err := yaml.Marshal(r)
+
+	a := assert.New(t)
+	a.NoError(err)
+	a.Contains(string(encoded), "\nx-tag-groups:\n")
+
+	r2 := &models.Root{}
+	a.NoError(yaml.Unmarshal(encoded, r2))
+
+	validateRootWithExtensions(a, r2)
+}
+
+func TestExtensions_JSON(t *testing.T) {
+	r := rootWithExtensions()
+
+	encoded, err := json.Marshal(r)
+
+	a := assert.New(t)
+	a.NoError(err)
+	a.Contains(string(encoded), `"x-internal":true`)
+
+	r2 := &models.Root{}
+	a.NoError(json.Unmarshal(encoded, r2))
+
+	validateRootWithExtensions(a, r2)
+}
+
+func TestExtensions_OnlySpecificationExtensions(t *testing.T) {
+	a := assert.New(t)
+
+	i := &models.Info{}
+	a.NoError(yaml.Unmarshal([]byte("title: title\nunknown: value\nx-logo: logo.png\n"), i))
+	a.Equal(models.Extensions{"x-logo": "logo.png"}, i.Extensions)
+
+	i2 := &models.Info{}
+	a.NoError(json.Unmarshal([]byte(`{"title":"title","unknown":"value"}`), i2))
+	a.Nil(i2.Extensions)
+
+	encoded, err := json.Marshal(&models.Contact{Extensions: models.Extensions{"x-team": "team"}})
+	a.NoError(err)
+	a.Equal(`{"x-team":"team"}`, string(encoded))
+}
+
 func TestMergePaths_PartialDuplicates(t *testing.T) {
 	item1 := `
 /the/path:
@@ -191,6 +326,25 @@ func TestMergePaths_PartialDuplicates(t *testing.T) {
 	a.Equal("query", parameters[2].In)
 }
 
+func TestAddOperation(t *testing.T) {
+	a := assert.New(t)
+
+	var pathItems models.PathItems
+	a.NoError(yaml.Unmarshal([]byte("/the/path:\n  summary: summary\n  get:\n    summary: the get summary\n"), &pathItems))
+
+	a.NoError(pathItems.AddOperation("/the/path", "POST", &models.Operation{Summary: "the post summary"}))
+	a.NoError(pathItems.AddOperation("/the/other/path", "delete", &models.Operation{Summary: "the delete summary"}))
+
+	a.Equal("summary", pathItems["/the/path"].Summary)
+	a.Equal("the get summary", pathItems["/the/path"].Get.Summary)
+	a.Equal("the post summary", pathItems["/the/path"].Post.Summary)
+	a.Equal("the delete summary", pathItems["/the/other/path"].Delete.Summary)
+
+	a.EqualError(pathItems.AddOperation("/the/path", "get", &models.Operation{}), "operation GET /the/path is already defined")
+	a.EqualError(pathItems.AddOperation("/the/path", "fetch", &models.Operation{}), "unknown method fetch")
+	a.Equal("the get summary", pathItems["/the/path"].Get.Summary)
+}
+
 func root() *models.Root {
 	return &models.Root{
 		OpenAPI: "3.0.2",
@@ -227,8 +381,9 @@ func root() *models.Root {
 			Links:           map[string]*models.Link{},
 			Callbacks:       map[string]*models.Callback{},
 		},
-		Security: []*models.SecurityRequirement{
-			{Name: []string{"something"}},
+		Security: []models.SecurityRequirement{
+			{"something": []string{"scope"}},
+			{},
 		},
 		Tags: []*models.Tag{
 			{
@@ -250,11 +405,181 @@ func validateRoot(a *assert.Assertions, r *models.Root) {
 	a.Equal("something", r.Servers[0].URL)
 	a.Equal("something", r.Paths["something"].Description)
 	a.NotNil(r.Components)
-	a.Equal("something", r.Security[0].Name[0])
+	a.Equal("scope", r.Security[0]["something"][0])
+	a.Empty(r.Security[1])
 	a.Equal("something", r.Tags[0].Name)
 	a.Equal("something", r.ExternalDocumentation.URL)
 }
 
+func operationWithSecurity() *models.Operation {
+	return &models.Operation{
+		Security: &[]models.SecurityRequirement{
+			{"apiKey": []string{}},
+			{"oauth": []string{"read", "write"}},
+		},
+	}
+}
+
+func validateOperationWithSecurity(a *assert.Assertions, o *models.Operation) {
+	security := *o.Security
+	a.Len(security, 2)
+	a.Contains(security[0], "apiKey")
+	a.Empty(security[0]["apiKey"])
+	a.Equal([]string{"read", "write"}, security[1]["oauth"])
+}
+
+func schema() *models.Schema {
+	multipleOf := 0.5
+	minimum := float64(1)
+	maximum := float64(10)
+	minLength := uint64(2)
+	maxLength := uint64(20)
+	minItems := uint64(3)
+	maxItems := uint64(30)
+	minProperties := uint64(4)
+	maxProperties := uint64(40)
+	return &models.Schema{
+		Title:       "title",
+		Description: "description",
+		Type:        "object",
+		Format:      "format",
+		Default:     "default",
+		Example:     "example",
+		Nullable:    true,
+		ReadOnly:    true,
+		WriteOnly:   true,
+		Deprecated:  true,
+		Discriminator: &models.Discriminator{
+			PropertyName: "kind",
+			Mapping:      map[string]string{"kind": "#/components/schemas/kind"},
+		},
+		XML: &models.XML{
+			Name:      "name",
+			Namespace: "namespace",
+			Prefix:    "prefix",
+			Attribute: true,
+			Wrapped:   true,
+		},
+		ExternalDocumentation: &models.ExternalDocumentation{URL: "url"},
+		AllOf:                 []*models.Schema{{Ref: "allOf"}},
+		OneOf:                 []*models.Schema{{Ref: "oneOf"}},
+		AnyOf:                 []*models.Schema{{Ref: "anyOf"}},
+		Not:                   &models.Schema{Ref: "not"},
+		Items:                 &models.Schema{Type: "string"},
+		Properties:            map[string]*models.Schema{"property": {Type: "integer"}},
+		AdditionalProperties:  &models.Schema{Type: "boolean"},
+		Required:              []string{"property"},
+		Enum:                  []interface{}{"a", "b"},
+		MultipleOf:            &multipleOf,
+		Minimum:               &minimum,
+		ExclusiveMinimum:      true,
+		Maximum:               &maximum,
+		ExclusiveMaximum:      true,
+		MinLength:             &minLength,
+		MaxLength:             &maxLength,
+		Pattern:               "^pattern$",
+		MinItems:              &minItems,
+		MaxItems:              &maxItems,
+		UniqueItems:           true,
+		MinProperties:         &minProperties,
+		MaxProperties:         &maxProperties,
+	}
+}
+
+func validateSchema(a *assert.Assertions, s *models.Schema) {
+	a.Equal("title", s.Title)
+	a.Equal("description", s.Description)
+	a.Equal("object", s.Type)
+	a.Equal("format", s.Format)
+	a.Equal("default", s.Default)
+	a.Equal("example", s.Example)
+	a.True(s.Nullable)
+	a.True(s.ReadOnly)
+	a.True(s.WriteOnly)
+	a.True(s.Deprecated)
+	a.Equal("kind", s.Discriminator.PropertyName)
+	a.Equal("#/components/schemas/kind", s.Discriminator.Mapping["kind"])
+	a.Equal("name", s.XML.Name)
+	a.Equal("namespace", s.XML.Namespace)
+	a.Equal("prefix", s.XML.Prefix)
+	a.True(s.XML.Attribute)
+	a.True(s.XML.Wrapped)
+	a.Equal("url", s.ExternalDocumentation.URL)
+	a.Equal("allOf", s.AllOf[0].Ref)
+	a.Equal("oneOf", s.OneOf[0].Ref)
+	a.Equal("anyOf", s.AnyOf[0].Ref)
+	a.Equal("not", s.Not.Ref)
+	a.Equal("string", s.Items.Type)
+	a.Equal("integer", s.Properties["property"].Type)
+	a.Equal("boolean", s.AdditionalProperties.(*models.Schema).Type)
+	a.Equal([]string{"property"}, s.Required)
+	a.Equal([]interface{}{"a", "b"}, s.Enum)
+	a.Equal(0.5, *s.MultipleOf)
+	a.Equal(float64(1), *s.Minimum)
+	a.True(s.ExclusiveMinimum)
+	a.Equal(float64(10), *s.Maximum)
+	a.True(s.ExclusiveMaximum)
+	a.Equal(uint64(2), *s.MinLength)
+	a.Equal(uint64(20), *s.MaxLength)
+	a.Equal("^pattern$", s.Pattern)
+	a.Equal(uint64(3), *s.MinItems)
+	a.Equal(uint64(30), *s.MaxItems)
+	a.True(s.UniqueItems)
+	a.Equal(uint64(4), *s.MinProperties)
+	a.Equal(uint64(40), *s.MaxProperties)
+}
+
+func rootWithExtensions() *models.Root {
+	return &models.Root{
+		OpenAPI:    "3.0.2",
+		Info:       &models.Info{Title: "title", Extensions: models.Extensions{"x-logo": "logo.png"}},
+		Extensions: models.Extensions{"x-tag-groups": []interface{}{"group"}},
+		Paths: map[string]*models.PathItem{
+			"/path": {
+				Post: &models.Operation{
+					Summary:    "summary",
+					Extensions: models.Extensions{"x-codegen-request-body-name": "body"},
+					RequestBody: &models.RequestBody{
+						Extensions: models.Extensions{"x-internal": true},
+					},
+				},
+			},
+		},
+		Components: &models.Components{
+			Schemas: map[string]*models.Schema{
+				"schema": {
+					Type:                 "object",
+					AdditionalProperties: true,
+					Extensions:           models.Extensions{"x-go-type": "Schema"},
+				},
+			},
+			SecuritySchemes: map[string]*models.SecurityScheme{
+				"oauth": {
+					Type: "oauth2",
+					Flows: &models.OAuthFlows{
+						Implicit: &models.OathFlowObject{Extensions: models.Extensions{"x-flow": "flow"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateRootWithExtensions(a *assert.Assertions, r *models.Root) {
+	a.Equal([]interface{}{"group"}, r.Extensions["x-tag-groups"])
+	a.Equal("title", r.Info.Title)
+	a.Equal("logo.png", r.Info.Extensions["x-logo"])
+	operation := r.Paths["/path"].Post
+	a.Equal("summary", operation.Summary)
+	a.Equal("body", operation.Extensions["x-codegen-request-body-name"])
+	a.Equal(true, operation.RequestBody.Extensions["x-internal"])
+	schema := r.Components.Schemas["schema"]
+	a.Equal("object", schema.Type)
+	a.Equal(true, schema.AdditionalProperties)
+	a.Equal("Schema", schema.Extensions["x-go-type"])
+	a.Equal("flow", r.Components.SecuritySchemes["oauth"].Flows.Implicit.Extensions["x-flow"])
+}
+
 func info() *models.Info {
 	return &models.Info{
 		Title:          "title",
@@ -373,6 +698,12 @@ func components() *models.Components {
 				Scheme:           "scheme",
 				BearerFormat:     "bearer",
 				OpenIdConnectUrl: "url",
+				Flows: &models.OAuthFlows{
+					Implicit: &models.OathFlowObject{
+						AuthorizationURL: "authorizationUrl",
+						Scopes:           map[string]string{"read": "read access"},
+					},
+				},
 			},
 		},
 		Links: map[string]*models.Link{
@@ -442,4 +773,6 @@ func validateComponents(a *assert.Assertions, c *models.Components) {
 	a.Equal("url", c.SecuritySchemes["securitySchema"].OpenIdConnectUrl)
 	a.Equal("scheme", c.SecuritySchemes["securitySchema"].Scheme)
 	a.Equal("type", c.SecuritySchemes["securitySchema"].Type)
+	a.Equal("authorizationUrl", c.SecuritySchemes["securitySchema"].Flows.Implicit.AuthorizationURL)
+	a.Equal("read access", c.SecuritySchemes["securitySchema"].Flows.Implicit.Scopes["read"])
 }